@@ -88,6 +88,14 @@ new sst.aws.Cron("InventoryTracker", {
   },
 });
 
+new sst.aws.Cron("SubscriptionRenewal", {
+  schedule: "rate(1 day)",
+  job: {
+    link: [bus, database, secret.StripeSecret, secret.ShippoSecret],
+    handler: "./packages/functions/src/cron/subscription.handler",
+  },
+});
+
 export const outputs = {
   auth: auth.url,
   api: api.url,
@@ -0,0 +1,4 @@
+ALTER TABLE `api_personal_token` ADD `label` varchar(255);--> statement-breakpoint
+ALTER TABLE `api_personal_token` ADD `scope` enum('read','cart','full') DEFAULT 'full' NOT NULL;--> statement-breakpoint
+ALTER TABLE `api_personal_token` ADD `time_expires` timestamp(3);--> statement-breakpoint
+ALTER TABLE `api_personal_token` ADD `time_used` timestamp(3);
\ No newline at end of file
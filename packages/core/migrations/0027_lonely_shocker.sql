@@ -0,0 +1,2 @@
+ALTER TABLE `user` ADD `default_address_id` char(30);--> statement-breakpoint
+ALTER TABLE `user` ADD `default_card_id` char(30);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `subscription` ADD `time_paused` timestamp(3);
\ No newline at end of file
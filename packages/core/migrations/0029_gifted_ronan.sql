@@ -0,0 +1,19 @@
+CREATE TABLE `promo` (
+	`id` char(30) NOT NULL,
+	`time_created` timestamp(3) NOT NULL DEFAULT (now()),
+	`time_updated` timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+	`time_deleted` timestamp(3),
+	`code` varchar(255) NOT NULL,
+	`kind` enum('percent','amount') NOT NULL,
+	`value` bigint NOT NULL,
+	`max_uses` int,
+	`uses` int NOT NULL DEFAULT 0,
+	`time_expires` timestamp(3),
+	CONSTRAINT `promo_id` PRIMARY KEY(`id`),
+	CONSTRAINT `promo_code_unique` UNIQUE(`code`)
+);
+--> statement-breakpoint
+ALTER TABLE `cart` ADD `promo_id` char(30);--> statement-breakpoint
+ALTER TABLE `order` ADD `discount_amount` bigint DEFAULT 0 NOT NULL;--> statement-breakpoint
+ALTER TABLE `order` ADD `promo_code` varchar(255);--> statement-breakpoint
+ALTER TABLE `cart` ADD CONSTRAINT `cart_promo_id_promo_id_fk` FOREIGN KEY (`promo_id`) REFERENCES `promo`(`id`) ON DELETE set null ON UPDATE no action;
\ No newline at end of file
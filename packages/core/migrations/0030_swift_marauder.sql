@@ -0,0 +1 @@
+ALTER TABLE `cart` ADD `shipping_method` text;
\ No newline at end of file
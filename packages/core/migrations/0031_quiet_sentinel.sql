@@ -0,0 +1,21 @@
+CREATE TABLE `auth_limit` (
+	`key` varchar(255) NOT NULL,
+	`time_created` timestamp(3) NOT NULL DEFAULT (now()),
+	`time_updated` timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+	`time_deleted` timestamp(3),
+	`count` int NOT NULL,
+	`time_reset` timestamp(3) NOT NULL,
+	CONSTRAINT `auth_limit_key` PRIMARY KEY(`key`)
+);
+--> statement-breakpoint
+CREATE TABLE `email_code` (
+	`email` varchar(255) NOT NULL,
+	`time_created` timestamp(3) NOT NULL DEFAULT (now()),
+	`time_updated` timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+	`time_deleted` timestamp(3),
+	`code` char(6) NOT NULL,
+	`attempts` int NOT NULL DEFAULT 0,
+	`sends` int NOT NULL DEFAULT 0,
+	`time_expires` timestamp(3) NOT NULL,
+	CONSTRAINT `email_code_email` PRIMARY KEY(`email`)
+);
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE `pairing_code` (
+	`code_hash` char(64) NOT NULL,
+	`user_id` char(30) NOT NULL,
+	`time_created` timestamp(3) NOT NULL DEFAULT (now()),
+	`time_updated` timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+	`time_deleted` timestamp(3),
+	`time_expires` timestamp(3) NOT NULL,
+	CONSTRAINT `pairing_code_code_hash` PRIMARY KEY(`code_hash`)
+);
+--> statement-breakpoint
+ALTER TABLE `pairing_code` ADD CONSTRAINT `pairing_code_user_id_user_id_fk` FOREIGN KEY (`user_id`) REFERENCES `user`(`id`) ON DELETE cascade ON UPDATE no action;
+--> statement-breakpoint
+DELETE FROM `user_fingerprint` WHERE `fingerprint` LIKE 'pair:%';
\ No newline at end of file
@@ -8,6 +8,8 @@ import { addressTable } from "./address.sql";
 import { Common } from "../common";
 import { Examples } from "../examples";
 import { Shippo } from "../shippo";
+import { VisibleError } from "../error";
+import { userTable } from "../user/user.sql";
 
 export module Address {
   export const Inner = z
@@ -95,6 +97,29 @@ export module Address {
     }),
   );
 
+  export const update = fn(
+    Inner.extend({ id: Info.shape.id }),
+    ({ id, ...input }) =>
+      useTransaction(async (tx) => {
+        const where = and(
+          eq(addressTable.id, id),
+          eq(addressTable.userID, useUserID()),
+        );
+        const exists = await tx
+          .select({ id: addressTable.id })
+          .from(addressTable)
+          .where(where)
+          .then((rows) => rows.length > 0);
+        if (!exists)
+          throw new VisibleError("input", "address.invalid", "Address not found");
+        const validated = await Shippo.assertValidAddress(input);
+        await tx
+          .update(addressTable)
+          .set({ address: validated })
+          .where(where);
+      }),
+  );
+
   export const remove = fn(z.string(), (input) =>
     useTransaction(async (tx) => {
       await tx
@@ -102,6 +127,15 @@ export module Address {
         .where(
           and(eq(addressTable.id, input), eq(addressTable.userID, useUserID())),
         );
+      await tx
+        .update(userTable)
+        .set({ defaultAddressID: null })
+        .where(
+          and(
+            eq(userTable.id, useUserID()),
+            eq(userTable.defaultAddressID, input),
+          ),
+        );
     }),
   );
 
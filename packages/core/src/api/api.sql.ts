@@ -1,5 +1,5 @@
 import { mysqlTable, varchar, mysqlEnum } from "drizzle-orm/mysql-core";
-import { id, timestamps, ulid } from "../drizzle/types";
+import { id, timestamp, timestamps, ulid } from "../drizzle/types";
 import { userTable } from "../user/user.sql";
 
 export const apiClientTable = mysqlTable("api_client", {
@@ -19,6 +19,10 @@ export const apiPersonalTokenTable = mysqlTable("api_personal_token", {
   ...id,
   ...timestamps,
   token: varchar("token", { length: 255 }).notNull(),
+  label: varchar("label", { length: 255 }),
+  scope: mysqlEnum("scope", ["read", "cart", "full"]).notNull().default("full"),
+  timeExpires: timestamp("time_expires"),
+  timeUsed: timestamp("time_used"),
   userID: ulid("user_id")
     .references(() => userTable.id, {
       onDelete: "cascade",
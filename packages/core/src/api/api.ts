@@ -8,6 +8,7 @@ import { randomBytes } from "crypto";
 import { Resource } from "sst";
 import { Common } from "../common";
 import { Examples } from "../examples";
+import { VisibleError } from "../error";
 
 export namespace Api {
   export namespace Client {
@@ -121,18 +122,40 @@ export namespace Api {
   }
 
   export namespace Personal {
+    export const Scope = z.enum(["read", "cart", "full"]).openapi({
+      description:
+        "What the token can do. `read` can view orders and products, `cart` can also manage the cart and place orders, `full` can do everything.",
+      example: Examples.Token.scope,
+    });
+    export type Scope = z.infer<typeof Scope>;
+
     export const Info = z
       .object({
         id: z.string().openapi({
           description: Common.IdDescription,
           example: Examples.Token.id,
         }),
+        label: z.string().nullable().openapi({
+          description: "Label to remember what the token is for.",
+          example: Examples.Token.label,
+        }),
+        scope: Scope,
         time: z
           .object({
             created: z.date().openapi({
               description: "The created time for the token.",
               example: Examples.Token.time.created,
             }),
+            expires: z.date().nullable().openapi({
+              description:
+                "When the token stops working, or null if it never expires.",
+              example: Examples.Token.time.expires,
+            }),
+            used: z.date().nullable().openapi({
+              description:
+                "When the token was last used, or null if it hasn't been.",
+              example: Examples.Token.time.used,
+            }),
           })
           .openapi({
             description: "Relevant timestamps for the token.",
@@ -152,21 +175,37 @@ export namespace Api {
 
     export type Info = z.infer<typeof Info>;
 
-    export async function create() {
-      const id = createID("apiPersonal");
-      const prefix = Resource.App.stage === "production" ? "live" : "test";
-      const token = `trm_${prefix}_` + randomBytes(10).toString("hex");
-      await db.insert(apiPersonalTokenTable).values({
-        id,
-        token,
-        userID: useUserID(),
-      });
+    export const create = fn(
+      z.object({
+        label: Info.shape.label.optional(),
+        scope: Scope.default("full"),
+        expires: z.coerce.date().optional(),
+      }),
+      async (input) => {
+        if (input.expires && input.expires.getTime() <= Date.now())
+          throw new VisibleError(
+            "input",
+            "token.expires",
+            "Expiry must be in the future",
+          );
+        const id = createID("apiPersonal");
+        const prefix = Resource.App.stage === "production" ? "live" : "test";
+        const token = `trm_${prefix}_` + randomBytes(10).toString("hex");
+        await db.insert(apiPersonalTokenTable).values({
+          id,
+          token,
+          label: input.label || null,
+          scope: input.scope,
+          timeExpires: input.expires,
+          userID: useUserID(),
+        });
 
-      return {
-        id,
-        token,
-      };
-    }
+        return {
+          id,
+          token,
+        };
+      },
+    );
 
     export const remove = fn(
       Info.shape.id,
@@ -205,8 +244,12 @@ export namespace Api {
     ): z.infer<typeof Info> {
       return {
         id: input.id,
+        label: input.label,
+        scope: input.scope,
         time: {
           created: input.timeCreated,
+          expires: input.timeExpires,
+          used: input.timeUsed,
         },
         token: obfuscate(input.token),
       };
@@ -220,15 +263,59 @@ export namespace Api {
         .then((rows) => serialize(rows.at(0)!));
     });
 
+    // Writing on every request isn't worth it, last used only needs to be
+    // roughly right.
+    const USED_INTERVAL = 60 * 1000;
+
     export async function fromToken(token: string) {
-      return db
+      const row = await db
         .select({
           id: apiPersonalTokenTable.id,
           userID: apiPersonalTokenTable.userID,
+          scope: apiPersonalTokenTable.scope,
+          timeExpires: apiPersonalTokenTable.timeExpires,
+          timeUsed: apiPersonalTokenTable.timeUsed,
         })
         .from(apiPersonalTokenTable)
         .where(eq(apiPersonalTokenTable.token, token))
         .then((rows) => rows.at(0));
+      if (!row) return;
+      if (row.timeExpires && row.timeExpires.getTime() <= Date.now()) return;
+      if (!row.timeUsed || Date.now() - row.timeUsed.getTime() > USED_INTERVAL)
+        await db
+          .update(apiPersonalTokenTable)
+          .set({ timeUsed: new Date() })
+          .where(eq(apiPersonalTokenTable.id, row.id));
+      return {
+        id: row.id,
+        userID: row.userID,
+        scope: row.scope,
+      };
+    }
+
+    // Routes each scope can use besides full, matched by method and path
+    // prefix. Everything else, including managing tokens, needs full.
+    const READ = [
+      { method: "GET", path: "/order" },
+      { method: "GET", path: "/product" },
+    ];
+    const ROUTES: Record<Exclude<Scope, "full">, typeof READ> = {
+      read: READ,
+      cart: [
+        ...READ,
+        { method: "*", path: "/cart" },
+        { method: "GET", path: "/address" },
+        { method: "GET", path: "/card" },
+      ],
+    };
+
+    export function allows(scope: Scope, method: string, path: string) {
+      if (scope === "full") return true;
+      return ROUTES[scope].some(
+        (route) =>
+          (route.method === "*" || route.method === method) &&
+          (path === route.path || path.startsWith(route.path + "/")),
+      );
     }
   }
 }
@@ -0,0 +1,21 @@
+import { char, int, mysqlTable, varchar } from "drizzle-orm/mysql-core";
+import { timestamp, timestamps } from "../drizzle/types";
+
+// One-time codes to sign in to SSH sessions without a key, one per email.
+export const emailCodeTable = mysqlTable("email_code", {
+  email: varchar("email", { length: 255 }).primaryKey(),
+  ...timestamps,
+  code: char("code", { length: 6 }).notNull(),
+  attempts: int("attempts").notNull().default(0),
+  sends: int("sends").notNull().default(0),
+  timeExpires: timestamp("time_expires").notNull(),
+});
+
+// Fixed window counters for unauthenticated requests, keyed by what they
+// limit, like ip:203.0.113.7.
+export const authLimitTable = mysqlTable("auth_limit", {
+  key: varchar("key", { length: 255 }).primaryKey(),
+  ...timestamps,
+  count: int("count").notNull(),
+  timeReset: timestamp("time_reset").notNull(),
+});
@@ -0,0 +1,156 @@
+import { z } from "zod";
+import { and, eq, gt, lt, sql } from "drizzle-orm";
+import { randomInt, timingSafeEqual } from "crypto";
+import { fn } from "../util/fn";
+import { useTransaction } from "../drizzle/transaction";
+import { authLimitTable, emailCodeTable } from "./auth.sql";
+import { VisibleError } from "../error";
+import { Email } from "../email";
+
+export module Auth {
+  const CODE_TTL = 10 * 60 * 1000;
+  // wrong guesses before a code stops working, a new one has to be sent
+  const CODE_ATTEMPTS = 5;
+  // times a pending code is mailed before the email has to wait for it to
+  // expire
+  const CODE_SENDS = 3;
+  const IP_SENDS = 10;
+  const IP_WINDOW = 60 * 60 * 1000;
+
+  function throttled() {
+    return new VisibleError(
+      "input",
+      "auth.throttled",
+      "Too many codes requested, try again later",
+    );
+  }
+
+  // limit counts a request against key, throwing once there have been max
+  // in the window.
+  async function limit(key: string, max: number, window: number) {
+    await useTransaction(async (tx) => {
+      const now = new Date();
+      const row = await tx
+        .select()
+        .from(authLimitTable)
+        .where(eq(authLimitTable.key, key))
+        .then((rows) => rows[0]);
+      if (!row || row.timeReset < now) {
+        const timeReset = new Date(now.getTime() + window);
+        await tx
+          .insert(authLimitTable)
+          .values({ key, count: 1, timeReset })
+          .onDuplicateKeyUpdate({ set: { count: 1, timeReset } });
+        return;
+      }
+      const result = await tx
+        .update(authLimitTable)
+        .set({ count: sql`${authLimitTable.count} + 1` })
+        .where(
+          and(eq(authLimitTable.key, key), lt(authLimitTable.count, max)),
+        );
+      if (result.rowsAffected === 0) throw throttled();
+    });
+  }
+
+  /**
+   * Mails a code to sign in as email. A code that's still pending is sent
+   * again instead of a new one, so there's only ever one to guess.
+   */
+  export const sendCode = fn(
+    z.object({
+      email: z.string().email(),
+      ip: z.string().optional(),
+    }),
+    async (input) => {
+      if (input.ip) await limit("ip:" + input.ip, IP_SENDS, IP_WINDOW);
+      const code = await useTransaction(async (tx) => {
+        const now = new Date();
+        const pending = await tx
+          .select()
+          .from(emailCodeTable)
+          .where(
+            and(
+              eq(emailCodeTable.email, input.email),
+              gt(emailCodeTable.timeExpires, now),
+              lt(emailCodeTable.attempts, CODE_ATTEMPTS),
+            ),
+          )
+          .then((rows) => rows[0]);
+        if (pending) {
+          const result = await tx
+            .update(emailCodeTable)
+            .set({ sends: sql`${emailCodeTable.sends} + 1` })
+            .where(
+              and(
+                eq(emailCodeTable.email, input.email),
+                lt(emailCodeTable.sends, CODE_SENDS),
+              ),
+            );
+          if (result.rowsAffected === 0) throw throttled();
+          return pending.code;
+        }
+        const code = randomInt(1_000_000).toString().padStart(6, "0");
+        const values = {
+          code,
+          attempts: 0,
+          sends: 1,
+          timeExpires: new Date(now.getTime() + CODE_TTL),
+        };
+        await tx
+          .insert(emailCodeTable)
+          .values({ email: input.email, ...values })
+          .onDuplicateKeyUpdate({ set: values });
+        return code;
+      });
+      await Email.send(
+        "auth",
+        input.email,
+        `Terminal code: ${code}`,
+        `Your terminal login code is ${code}`,
+      );
+    },
+  );
+
+  /**
+   * Uses up the code sent to email. Every guess counts against the code,
+   * and it's deleted once it's used.
+   */
+  export const verifyCode = fn(
+    z.object({
+      email: z.string(),
+      code: z.string(),
+    }),
+    (input) =>
+      useTransaction(async (tx) => {
+        const counted = await tx
+          .update(emailCodeTable)
+          .set({ attempts: sql`${emailCodeTable.attempts} + 1` })
+          .where(
+            and(
+              eq(emailCodeTable.email, input.email),
+              gt(emailCodeTable.timeExpires, new Date()),
+              lt(emailCodeTable.attempts, CODE_ATTEMPTS),
+            ),
+          );
+        if (counted.rowsAffected === 0) return false;
+        const row = await tx
+          .select({ code: emailCodeTable.code })
+          .from(emailCodeTable)
+          .where(eq(emailCodeTable.email, input.email))
+          .then((rows) => rows[0]);
+        const guess = Buffer.from(input.code.padEnd(6).slice(0, 6));
+        if (!row || !timingSafeEqual(Buffer.from(row.code), guess))
+          return false;
+        const used = await tx
+          .delete(emailCodeTable)
+          .where(
+            and(
+              eq(emailCodeTable.email, input.email),
+              eq(emailCodeTable.code, row.code),
+            ),
+          );
+        return used.rowsAffected === 1;
+      }),
+  );
+}
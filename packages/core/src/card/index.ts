@@ -152,6 +152,12 @@ export module Card {
       await tx
         .delete(cardTable)
         .where(and(eq(cardTable.id, input), eq(cardTable.userID, useUserID())));
+      await tx
+        .update(userTable)
+        .set({ defaultCardID: null })
+        .where(
+          and(eq(userTable.id, useUserID()), eq(userTable.defaultCardID, input)),
+        );
     }),
   );
 
@@ -4,6 +4,7 @@ import { userTable } from "../user/user.sql";
 import { productVariantTable } from "../product/product.sql";
 import { cardTable } from "../card/card.sql";
 import { addressTable } from "../address/address.sql";
+import { promoTable } from "../promo/promo.sql";
 
 export const cartItemTable = mysqlTable(
   "cart_item",
@@ -44,6 +45,10 @@ export const cartTable = mysqlTable("cart", {
   }),
   shippingAmount: dollar("shipping_amount"),
   shippingService: text("shipping_service"),
+  shippingMethod: text("shipping_method"),
   shippoRateID: text("shippo_rate_id"),
   shippingDeliveryEstimate: text("shipping_delivery_estimate"),
+  promoID: ulid("promo_id").references(() => promoTable.id, {
+    onDelete: "set null",
+  }),
 });
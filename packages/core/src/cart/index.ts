@@ -14,6 +14,8 @@ import { Examples } from "../examples";
 import { addressTable } from "../address/address.sql";
 import { Address } from "../address";
 import { Product } from "../product";
+import { Promo } from "../promo";
+import { promoTable } from "../promo/promo.sql";
 
 export module Cart {
   export const Item = z
@@ -76,10 +78,15 @@ export module Cart {
               "Shipping amount of the current user's cart, in cents (USD).",
             example: Examples.Cart.amount.shipping,
           }),
+          discount: z.number().int().optional().openapi({
+            description:
+              "Discount from the promo applied to the current user's cart, in cents (USD).",
+            example: Examples.Cart.amount.discount,
+          }),
         })
         .openapi({
           description:
-            "The subtotal and shipping amounts for the current user's cart.",
+            "The subtotal, shipping and discount amounts for the current user's cart.",
           example: Examples.Cart.amount,
         }),
       shipping: z
@@ -98,6 +105,27 @@ export module Cart {
           description: "Shipping information for the current user's cart.",
           example: Examples.Cart.shipping,
         }),
+      promo: Promo.Info.optional().openapi({
+        description: "Promo code applied to the current user's cart.",
+        example: Examples.Cart.promo,
+      }),
+      freeShipping: z
+        .object({
+          threshold: z.number().int().openapi({
+            description:
+              "Subtotal from which shipping is free, in cents (USD).",
+            example: Examples.Cart.freeShipping.threshold,
+          }),
+          country: z.string().openapi({
+            description:
+              "Country code (ISO 3166-1) that orders must ship to for free shipping.",
+            example: Examples.Cart.freeShipping.country,
+          }),
+        })
+        .openapi({
+          description: "When the current user's cart ships for free.",
+          example: Examples.Cart.freeShipping,
+        }),
     })
     .openapi({
       ref: "Cart",
@@ -107,6 +135,40 @@ export module Cart {
 
   export type Info = z.infer<typeof Info>;
 
+  export const ShippingRate = z
+    .object({
+      id: z.string().openapi({
+        description: "ID of the shipping method.",
+        example: Examples.ShippingRate.id,
+      }),
+      service: z.string().openapi({
+        description: "Shipping service name.",
+        example: Examples.ShippingRate.service,
+      }),
+      amount: z.number().int().openapi({
+        description:
+          "Shipping amount for the current user's cart, in cents (USD).",
+        example: Examples.ShippingRate.amount,
+      }),
+      timeframe: z.string().optional().openapi({
+        description: "Shipping timeframe provided by the shipping carrier.",
+        example: Examples.ShippingRate.timeframe,
+      }),
+    })
+    .openapi({
+      ref: "ShippingRate",
+      description:
+        "A shipping method available for the current user's cart and its rate.",
+      example: Examples.ShippingRate,
+    });
+
+  export type ShippingRate = z.infer<typeof ShippingRate>;
+
+  export const FreeShipping = {
+    threshold: 40 * 100,
+    country: "US",
+  };
+
   export async function get() {
     return createTransaction(async (tx): Promise<Info> => {
       const cart = await tx
@@ -116,10 +178,12 @@ export module Cart {
           shippingAmount: cartTable.shippingAmount,
           shippingService: cartTable.shippingService,
           shippingDeliveryEstimate: cartTable.shippingDeliveryEstimate,
+          promo: promoTable,
         })
         .from(cartTable)
         .leftJoin(cardTable, eq(cartTable.cardID, cardTable.id))
         .leftJoin(addressTable, eq(cartTable.addressID, addressTable.id))
+        .leftJoin(promoTable, eq(cartTable.promoID, promoTable.id))
         .where(eq(cartTable.userID, useUserID()))
         .then((rows) => rows[0]);
       if (!cart)
@@ -130,16 +194,21 @@ export module Cart {
             subtotal: 0,
           },
           subtotal: 0,
+          freeShipping: FreeShipping,
         };
       const items = await list();
       const subtotal = items.reduce((acc, item) => item.subtotal + acc, 0);
+      const promo = cart.promo ? Promo.serialize(cart.promo) : undefined;
       return {
         items,
         subtotal,
         amount: {
           subtotal,
           shipping: cart.shippingAmount ?? undefined,
+          discount: promo ? Promo.discount(promo, subtotal) : undefined,
         },
+        promo,
+        freeShipping: FreeShipping,
         cardID: cart.cardID || undefined,
         addressID: cart.addressID || undefined,
         shipping: {
@@ -150,22 +219,106 @@ export module Cart {
     });
   }
 
-  const FREE_SHIPPING_THRESHOLD = 40 * 100;
-  export async function calculateShipping(
+  export async function calculateShippingRates(
     subtotal: number,
     ounces: number,
     address: Address.Inner,
   ) {
-    const rate = await Shippo.createShipmentRate({ ounces, address, subtotal });
-    if (address.country === "US") {
-      return {
-        ...rate,
-        shippingAmount: subtotal >= FREE_SHIPPING_THRESHOLD ? 0 : 800,
+    const rates = await Shippo.createShipmentRates({
+      ounces,
+      address,
+      subtotal,
+    });
+    // US orders ship at a flat rate with the cheapest service, faster
+    // services cost what the carrier charges
+    if (address.country === FreeShipping.country) {
+      rates[0] = {
+        ...rates[0]!,
+        shippingAmount: subtotal >= FreeShipping.threshold ? 0 : 800,
       };
     }
-    return rate;
+    // the flat rate can cost more than a faster service, keep cheapest first
+    return rates.sort((a, b) => a.shippingAmount - b.shippingAmount);
   }
 
+  // calculateShipping is the cheapest rate, what carts ship with until a
+  // method is picked and what subscriptions always ship with.
+  export async function calculateShipping(
+    subtotal: number,
+    ounces: number,
+    address: Address.Inner,
+  ) {
+    const rates = await calculateShippingRates(subtotal, ounces, address);
+    return rates[0]!;
+  }
+
+  async function quote(addressID: string) {
+    const response = await useTransaction((tx) =>
+      tx
+        .select({
+          count: sum(cartItemTable.quantity).mapWith(Number),
+          subtotal:
+            sql`sum(${productVariantTable.price} * ${cartItemTable.quantity})`.mapWith(
+              Number,
+            ),
+          address: addressTable.address,
+        })
+        .from(cartItemTable)
+        .innerJoin(
+          productVariantTable,
+          eq(productVariantTable.id, cartItemTable.productVariantID),
+        )
+        .innerJoin(addressTable, eq(addressTable.id, addressID))
+        .where(eq(cartItemTable.userID, useUserID()))
+        .then((rows) => rows[0]!),
+    );
+    const weight = response.count * Product.TEMPORARY_FIXED_WEIGHT_OZ;
+    return calculateShippingRates(response.subtotal, weight, response.address);
+  }
+
+  async function cartAddressID() {
+    const addressID = await useTransaction((tx) =>
+      tx
+        .select({ addressID: cartTable.addressID })
+        .from(cartTable)
+        .where(eq(cartTable.userID, useUserID()))
+        .then((rows) => rows[0]?.addressID),
+    );
+    if (!addressID)
+      throw new VisibleError(
+        "input",
+        "shipping.address",
+        "Set a shipping address first",
+      );
+    return addressID;
+  }
+
+  export async function listShipping() {
+    const rates = await quote(await cartAddressID());
+    return rates.map(
+      (rate): ShippingRate => ({
+        id: rate.shippingMethod,
+        service: rate.shippingService,
+        amount: rate.shippingAmount,
+        timeframe: rate.shippingDeliveryEstimate || undefined,
+      }),
+    );
+  }
+
+  export const setShipping = fn(ShippingRate.shape.id, async (method) => {
+    const rates = await quote(await cartAddressID());
+    const rate = rates.find((rate) => rate.shippingMethod === method);
+    if (!rate)
+      throw new VisibleError(
+        "input",
+        "shipping.method",
+        "Shipping method not available",
+      );
+    await useTransaction((tx) =>
+      tx.update(cartTable).set(rate).where(eq(cartTable.userID, useUserID())),
+    );
+  });
+
   export const list = () =>
     useTransaction(async (tx) => {
       return tx
@@ -191,29 +344,8 @@ export module Cart {
     });
 
   export const setAddress = fn(z.string(), async (addressID) => {
-    const shippingInfo = await useTransaction(async (tx) => {
-      const response = await tx
-        .select({
-          count: sum(cartItemTable.quantity).mapWith(Number),
-          subtotal:
-            sql`sum(${productVariantTable.price} * ${cartItemTable.quantity})`.mapWith(
-              Number,
-            ),
-          address: addressTable.address,
-        })
-        .from(cartItemTable)
-        .innerJoin(
-          productVariantTable,
-          eq(productVariantTable.id, cartItemTable.productVariantID),
-        )
-        .innerJoin(addressTable, eq(addressTable.id, addressID))
-        .where(eq(cartItemTable.userID, useUserID()))
-        .then((rows) => rows[0]!);
-
-      const weight = response.count * Product.TEMPORARY_FIXED_WEIGHT_OZ;
-      const address = response.address;
-      return await calculateShipping(response.subtotal, weight, address);
-    });
+    // a new address starts over with the cheapest method
+    const shippingInfo = (await quote(addressID))[0]!;
 
     await useTransaction(async (tx) => {
       const id = await tx
@@ -267,6 +399,34 @@ export module Cart {
     }),
   );
 
+  export const setPromo = fn(z.string().min(1), async (code) => {
+    const promo = await Promo.fromCode(code);
+    await useTransaction(async (tx) => {
+      await tx
+        .insert(cartTable)
+        .values({
+          userID: useUserID(),
+          promoID: promo.id,
+          id: createID("cart"),
+        })
+        .onDuplicateKeyUpdate({
+          set: {
+            promoID: promo.id,
+          },
+        });
+    });
+    return Promo.serialize(promo);
+  });
+
+  export async function removePromo() {
+    return useTransaction(async (tx) =>
+      tx
+        .update(cartTable)
+        .set({ promoID: null })
+        .where(eq(cartTable.userID, useUserID())),
+    );
+  }
+
   export const setItem = fn(
     z.object({
       id: z.string().optional(),
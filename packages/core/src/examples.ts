@@ -53,12 +53,31 @@ export module Examples {
     subtotal: 4400,
   };
 
+  export const Promo = {
+    code: "CONF2026",
+    kind: "percent" as const,
+    value: 20,
+  };
+
+  export const ShippingRate = {
+    id: "usps_priority",
+    service: "USPS Priority Mail",
+    amount: 1200,
+    timeframe: "1-3 days",
+  };
+
   export const Cart = {
     subtotal: CartItem.subtotal,
     items: [CartItem],
     amount: {
       subtotal: CartItem.subtotal,
       shipping: 800,
+      discount: Math.floor((CartItem.subtotal * Promo.value) / 100),
+    },
+    promo: Promo,
+    freeShipping: {
+      threshold: 4000,
+      country: "US",
     },
     addressID: Shipping.id,
     cardID: Card.id,
@@ -94,6 +113,8 @@ export module Examples {
     email: "john@example.com",
     fingerprint: "183ded44-24d0-480e-9908-c022eff8d111",
     stripeCustomerID: "cus_XXXXXXXXXXXXXXXXX",
+    defaultAddressID: Id("userShipping"),
+    defaultCardID: Id("card"),
   };
 
   export const Profile = {
@@ -108,16 +129,34 @@ export module Examples {
     cardID: Card.id,
     frequency: "monthly" as const,
     next: new Date("2025-02-01 19:36:19.000"),
+    paused: false,
   };
 
   export const Token = {
     id: Id("apiPersonal"),
     token: "trm_test_******XXXX",
+    label: "CI",
+    scope: "read" as const,
+    time: {
+      created: new Date("2024-06-29 19:36:19.000"),
+      expires: new Date("2024-09-27 19:36:19.000"),
+      used: new Date("2024-07-01 08:12:45.000"),
+    },
+  };
+
+  export const Key = {
+    id: "key_XXXXXXXXXXXXXXXXXXXXXXXXXX",
+    fingerprint: "SHA256:vBBsZ9l3GS35eVLQeVJ4Li0e3i/E7+fCcIQb2M1kGt0",
     time: {
       created: new Date("2024-06-29 19:36:19.000"),
     },
   };
 
+  export const KeyPairing = {
+    code: "7KQ2-M9XD",
+    expires: new Date("2024-06-29 19:46:19.000"),
+  };
+
   export const App = {
     id: Id("apiClient"),
     secret: Id("apiSecret"),
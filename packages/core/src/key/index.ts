@@ -0,0 +1,171 @@
+import { z } from "zod";
+import { and, eq, gt, lt } from "drizzle-orm";
+import { createHash, randomInt } from "crypto";
+import { fn } from "../util/fn";
+import { useTransaction } from "../drizzle/transaction";
+import { userFingerprintTable } from "../user/user.sql";
+import { pairingCodeTable } from "./key.sql";
+import { useUserID } from "../actor";
+import { Common } from "../common";
+import { Examples } from "../examples";
+import { VisibleError } from "../error";
+
+export module Key {
+  export const Info = z
+    .object({
+      id: z.string().openapi({
+        description: Common.IdDescription,
+        example: Examples.Key.id,
+      }),
+      fingerprint: z.string().openapi({
+        description:
+          "Fingerprint of the SSH key, as printed by `ssh-keygen -l`.",
+        example: Examples.Key.fingerprint,
+      }),
+      time: z
+        .object({
+          created: z.date().openapi({
+            description: "The time the key was linked to the account.",
+            example: Examples.Key.time.created,
+          }),
+        })
+        .openapi({
+          description: "Relevant timestamps for the key.",
+          example: Examples.Key.time,
+        }),
+    })
+    .openapi({
+      ref: "Key",
+      description: "An SSH key that signs in to the account.",
+      example: Examples.Key,
+    });
+
+  export type Info = z.infer<typeof Info>;
+
+  export const Pairing = z
+    .object({
+      code: z.string().openapi({
+        description:
+          "Code to enter from the SSH session of the key to link. It can only be used once.",
+        example: Examples.KeyPairing.code,
+      }),
+      expires: z.date().openapi({
+        description: "The time the code stops working.",
+        example: Examples.KeyPairing.expires,
+      }),
+    })
+    .openapi({
+      ref: "KeyPairing",
+      description: "A short-lived code to link another SSH key to the account.",
+      example: Examples.KeyPairing,
+    });
+
+  const PAIRING_TTL = 10 * 60 * 1000;
+  const PAIRING_ALPHABET = "0123456789ABCDEFGHJKMNPQRSTVWXYZ";
+
+  function pairingHash(code: string) {
+    const normalized = code.toUpperCase().replace(/[^0-9A-Z]/g, "");
+    return createHash("sha256").update(normalized).digest("hex");
+  }
+
+  function id(fingerprint: string) {
+    return (
+      "key_" +
+      createHash("sha256").update(fingerprint).digest("hex").slice(0, 26)
+    );
+  }
+
+  export async function list(): Promise<Info[]> {
+    return useTransaction((tx) =>
+      tx
+        .select()
+        .from(userFingerprintTable)
+        .where(eq(userFingerprintTable.userID, useUserID()))
+        .then((rows) =>
+          rows.map((row) => ({
+            id: id(row.fingerprint),
+            fingerprint: row.fingerprint,
+            time: {
+              created: row.timeCreated,
+            },
+          })),
+        ),
+    );
+  }
+
+  export const remove = fn(Info.shape.id, async (input) => {
+    const keys = await list();
+    const match = keys.find((key) => key.id === input);
+    if (!match)
+      throw new VisibleError("input", "key.not_found", "Key not found");
+    if (keys.length === 1)
+      throw new VisibleError(
+        "input",
+        "key.last",
+        "Link another key before removing the last one",
+      );
+    await useTransaction((tx) =>
+      tx
+        .delete(userFingerprintTable)
+        .where(
+          and(
+            eq(userFingerprintTable.userID, useUserID()),
+            eq(userFingerprintTable.fingerprint, match.fingerprint),
+          ),
+        ),
+    );
+  });
+
+  export async function pair(): Promise<z.infer<typeof Pairing>> {
+    let code = "";
+    for (let i = 0; i < 8; i++)
+      code += PAIRING_ALPHABET[randomInt(PAIRING_ALPHABET.length)];
+    code = code.slice(0, 4) + "-" + code.slice(4);
+
+    const timeExpires = new Date(Date.now() + PAIRING_TTL);
+    await useTransaction(async (tx) => {
+      // codes that were never used
+      await tx
+        .delete(pairingCodeTable)
+        .where(
+          and(
+            eq(pairingCodeTable.userID, useUserID()),
+            lt(pairingCodeTable.timeExpires, new Date()),
+          ),
+        );
+      await tx.insert(pairingCodeTable).values({
+        codeHash: pairingHash(code),
+        userID: useUserID(),
+        timeExpires,
+      });
+    });
+    return {
+      code,
+      expires: timeExpires,
+    };
+  }
+
+  /**
+   * Uses up a pairing code and returns the account it links to, if it
+   * hasn't expired.
+   */
+  export const claim = fn(z.string(), (code) =>
+    useTransaction(async (tx) => {
+      const codeHash = pairingHash(code);
+      const match = await tx
+        .select()
+        .from(pairingCodeTable)
+        .where(
+          and(
+            eq(pairingCodeTable.codeHash, codeHash),
+            gt(pairingCodeTable.timeExpires, new Date()),
+          ),
+        )
+        .then((rows) => rows.at(0));
+      await tx
+        .delete(pairingCodeTable)
+        .where(eq(pairingCodeTable.codeHash, codeHash));
+      return match?.userID;
+    }),
+  );
+}
@@ -0,0 +1,15 @@
+import { char, mysqlTable } from "drizzle-orm/mysql-core";
+import { timestamp, timestamps, ulid } from "../drizzle/types";
+import { userTable } from "../user/user.sql";
+
+// Codes to link another SSH key to an account, only their hash is stored.
+export const pairingCodeTable = mysqlTable("pairing_code", {
+  codeHash: char("code_hash", { length: 64 }).primaryKey(),
+  userID: ulid("user_id")
+    .references(() => userTable.id, {
+      onDelete: "cascade",
+    })
+    .notNull(),
+  ...timestamps,
+  timeExpires: timestamp("time_expires").notNull(),
+});
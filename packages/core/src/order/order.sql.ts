@@ -1,4 +1,11 @@
-import { int, json, mysqlTable, text, unique } from "drizzle-orm/mysql-core";
+import {
+  int,
+  json,
+  mysqlTable,
+  text,
+  unique,
+  varchar,
+} from "drizzle-orm/mysql-core";
 import {
   address,
   dollar,
@@ -21,6 +28,8 @@ export const orderTable = mysqlTable("order", {
   }),
   shippingAddress: address("shipping_address").notNull(),
   shippingAmount: dollar("shipping_amount").notNull(),
+  discountAmount: dollar("discount_amount").notNull().default(0),
+  promoCode: varchar("promo_code", { length: 255 }),
   card: json("card").$type<Omit<Card.Info, "id">>(),
   trackingNumber: text("tracking_number"),
   trackingURL: text("tracking_url"),
@@ -43,6 +43,8 @@ import { addressTable } from "../address/address.sql";
 import { Product } from "../product";
 import { Cart } from "../cart";
 import { filter, useFilterContext } from "../product/filter";
+import { Promo } from "../promo";
+import { promoTable } from "../promo/promo.sql";
 
 export module Order {
   export const Item = z
@@ -93,9 +95,15 @@ export module Order {
             description: "Subtotal amount of the order, in cents (USD).",
             example: Examples.Order.amount.subtotal,
           }),
+          discount: z.number().int().optional().openapi({
+            description:
+              "Discount applied to the order by a promo code, in cents (USD).",
+            example: Examples.Order.amount.discount,
+          }),
         })
         .openapi({
-          description: "The subtotal and shipping amounts of the order.",
+          description:
+            "The subtotal, shipping and discount amounts of the order.",
           example: Examples.Order.amount,
         }),
       tracking: z
@@ -167,6 +175,7 @@ export module Order {
                 (acc, row) => acc + row.order_item!.amount,
                 0,
               ),
+              discount: group[0].order.discountAmount || undefined,
             },
             tracking: {
               service: group[0].cart.shippingService || undefined,
@@ -206,6 +215,7 @@ export module Order {
                 (acc, row) => acc + row.order_item.amount,
                 0,
               ),
+              discount: rows[0]!.order.discountAmount || undefined,
             },
             tracking: {
               number: rows[0]!.order.trackingNumber || undefined,
@@ -257,11 +267,13 @@ export module Order {
           email: userTable.email,
           shippingAmount: cartTable.shippingAmount,
           shippoRateID: cartTable.shippoRateID,
+          promoCode: promoTable.code,
         })
         .from(cartTable)
         .innerJoin(addressTable, eq(cartTable.addressID, addressTable.id))
         .innerJoin(cardTable, eq(cartTable.cardID, cardTable.id))
         .innerJoin(userTable, eq(cartTable.userID, userTable.id))
+        .leftJoin(promoTable, eq(cartTable.promoID, promoTable.id))
         .where(eq(cartTable.userID, userID))
         .then((rows) => rows[0]);
       return { items, cart };
@@ -280,39 +292,49 @@ export module Order {
     const subtotal = items.reduce((acc, item) => acc + item.subtotal, 0);
     const shipping = cart.shippingAmount;
     if (shipping === null) throw new Error("Shipping amount not set");
+    // revalidate in case the promo expired or ran out since it was applied
+    const promo = cart.promoCode
+      ? await Promo.fromCode(cart.promoCode)
+      : undefined;
+    const discount = promo ? Promo.discount(promo, subtotal) : 0;
+    const total = subtotal + shipping - discount;
+    if (promo) await Promo.reserve(promo, discount);
+    let placed = false;
     try {
-      const payment = [
-        "usr_01J1JGH7NH2HZ6DGAGT8SK2KE3",
-        "usr_01J1KHKPA8QK82MBHQDBQP78XK",
-        "usr_01J1KHPJ88QEFEQ6K27QA9C4WN",
-        "usr_01JG4BDDCKTY6CYWF6JXKVPNNT",
-      ].includes(userID)
-        ? undefined
-        : await stripe.paymentIntents.create({
-            amount: subtotal + shipping,
-            automatic_payment_methods: {
-              enabled: true,
-              allow_redirects: "never",
-            },
-            confirm: true,
-            currency: "usd",
-            shipping: {
-              name: cart.shipping.name,
-              address: {
-                city: cart.shipping.city,
-                line1: cart.shipping.street1,
-                line2: cart.shipping.street2,
-                postal_code: cart.shipping.zip,
-                state: cart.shipping.province,
-                country: cart.shipping.country,
+      const payment =
+        total <= 0 ||
+        [
+          "usr_01J1JGH7NH2HZ6DGAGT8SK2KE3",
+          "usr_01J1KHKPA8QK82MBHQDBQP78XK",
+          "usr_01J1KHPJ88QEFEQ6K27QA9C4WN",
+          "usr_01JG4BDDCKTY6CYWF6JXKVPNNT",
+        ].includes(userID)
+          ? undefined
+          : await stripe.paymentIntents.create({
+              amount: total,
+              automatic_payment_methods: {
+                enabled: true,
+                allow_redirects: "never",
               },
-            },
-            customer: cart.stripeCustomerID,
-            metadata: {
-              orderID,
-            },
-            payment_method: cart.card.stripePaymentMethodID,
-          });
+              confirm: true,
+              currency: "usd",
+              shipping: {
+                name: cart.shipping.name,
+                address: {
+                  city: cart.shipping.city,
+                  line1: cart.shipping.street1,
+                  line2: cart.shipping.street2,
+                  postal_code: cart.shipping.zip,
+                  state: cart.shipping.province,
+                  country: cart.shipping.country,
+                },
+              },
+              customer: cart.stripeCustomerID,
+              metadata: {
+                orderID,
+              },
+              payment_method: cart.card.stripePaymentMethodID,
+            });
       return createTransaction(async (tx) => {
         await tx.insert(orderTable).values({
           id: orderID,
@@ -321,6 +343,8 @@ export module Order {
           stripePaymentIntentID: payment?.id,
           shippingAddress: cart.shipping,
           shippingAmount: shipping,
+          discountAmount: discount,
+          promoCode: promo?.code,
           shippoRateID: cart.shippoRateID,
           card: {
             brand: cart.card.brand,
@@ -341,12 +365,20 @@ export module Order {
           })),
         );
         await tx.delete(cartItemTable).where(eq(cartItemTable.userID, userID));
+        if (promo) {
+          await tx
+            .update(cartTable)
+            .set({ promoID: null })
+            .where(eq(cartTable.userID, userID));
+        }
         await afterTx(() =>
           bus.publish(Resource.Bus, Event.Created, { orderID }),
         );
+        placed = true;
         return orderID;
       });
     } catch (ex: unknown) {
+      if (promo && !placed) await Promo.release(promo, discount);
       if (ex instanceof Stripe.errors.StripeCardError) {
         throw new VisibleError("input", "payment.invalid", ex.message);
       }
@@ -371,9 +403,21 @@ export module Order {
             email: userTable.email,
           })
           .from(userTable)
-          .innerJoin(addressTable, eq(cartTable.addressID, addressTable.id))
-          .innerJoin(cardTable, eq(cartTable.cardID, cardTable.id))
-          .where(eq(cartTable.userID, userID))
+          .innerJoin(
+            addressTable,
+            and(
+              eq(addressTable.id, input.addressID),
+              eq(addressTable.userID, userTable.id),
+            ),
+          )
+          .innerJoin(
+            cardTable,
+            and(
+              eq(cardTable.id, input.cardID),
+              eq(cardTable.userID, userTable.id),
+            ),
+          )
+          .where(eq(userTable.id, userID))
           .then((rows) => rows[0]),
       );
       if (!match) throw new Error("Card or address not found");
@@ -481,7 +525,7 @@ export module Order {
             id: createID("cartItem"),
             amount: item.price,
             productVariantID: item.id,
-            quantity: item.weight,
+            quantity: item.quantity,
             orderID,
           });
         }
@@ -0,0 +1,136 @@
+import { z } from "zod";
+import { and, eq, gte, isNull, lt, or, sql } from "drizzle-orm";
+import { useTransaction } from "../drizzle/transaction";
+import { promoTable } from "./promo.sql";
+import { VisibleError } from "../error";
+import { Examples } from "../examples";
+
+export module Promo {
+  export const Info = z
+    .object({
+      code: z.string().openapi({
+        description: "Code entered to apply the promo.",
+        example: Examples.Promo.code,
+      }),
+      kind: z.enum(["percent", "amount"]).openapi({
+        description:
+          "Whether the promo takes a percentage or a fixed amount off the subtotal.",
+        example: Examples.Promo.kind,
+      }),
+      value: z.number().int().openapi({
+        description:
+          "Percentage off for percent promos, or the balance left in cents (USD) for amount promos.",
+        example: Examples.Promo.value,
+      }),
+    })
+    .openapi({
+      ref: "Promo",
+      description: "A promo or gift code applied to a cart.",
+      example: Examples.Promo,
+    });
+
+  export type Info = z.infer<typeof Info>;
+
+  export function discount(
+    promo: Pick<Info, "kind" | "value">,
+    subtotal: number,
+  ) {
+    if (promo.kind === "percent")
+      return Math.floor((subtotal * Math.min(promo.value, 100)) / 100);
+    return Math.min(promo.value, subtotal);
+  }
+
+  export function fromCode(code: string) {
+    return useTransaction(async (tx) => {
+      const promo = await tx
+        .select()
+        .from(promoTable)
+        .where(eq(promoTable.code, code.trim().toUpperCase()))
+        .then((rows) => rows[0]);
+      if (!promo || promo.timeDeleted)
+        throw new VisibleError("input", "promo.invalid", "Invalid promo code");
+      if (promo.timeExpires && promo.timeExpires < new Date())
+        throw new VisibleError(
+          "input",
+          "promo.expired",
+          "This promo code has expired",
+        );
+      if (
+        (promo.maxUses !== null && promo.uses >= promo.maxUses) ||
+        (promo.kind === "amount" && promo.value <= 0)
+      )
+        throw used();
+      return promo;
+    });
+  }
+
+  function used() {
+    return new VisibleError(
+      "input",
+      "promo.used",
+      "This promo code has already been used",
+    );
+  }
+
+  /**
+   * Takes a use of the promo, and the discount off an amount promo's
+   * balance, before an order is charged. The update is conditional so two
+   * orders can't both take the last of it.
+   */
+  export function reserve(
+    promo: typeof promoTable.$inferSelect,
+    discount: number,
+  ) {
+    return useTransaction(async (tx) => {
+      const result = await tx
+        .update(promoTable)
+        .set({
+          uses: sql`${promoTable.uses} + 1`,
+          ...(promo.kind === "amount" && {
+            value: sql`${promoTable.value} - ${discount}`,
+          }),
+        })
+        .where(
+          and(
+            eq(promoTable.id, promo.id),
+            or(
+              isNull(promoTable.maxUses),
+              lt(promoTable.uses, promoTable.maxUses),
+            ),
+            promo.kind === "amount"
+              ? gte(promoTable.value, discount)
+              : undefined,
+          ),
+        );
+      if (result.rowsAffected === 0) throw used();
+    });
+  }
+
+  /**
+   * Gives back what reserve took, for an order that wasn't placed.
+   */
+  export function release(
+    promo: typeof promoTable.$inferSelect,
+    discount: number,
+  ) {
+    return useTransaction(async (tx) => {
+      await tx
+        .update(promoTable)
+        .set({
+          uses: sql`${promoTable.uses} - 1`,
+          ...(promo.kind === "amount" && {
+            value: sql`${promoTable.value} + ${discount}`,
+          }),
+        })
+        .where(eq(promoTable.id, promo.id));
+    });
+  }
+
+  export function serialize(input: typeof promoTable.$inferSelect): Info {
+    return {
+      code: input.code,
+      kind: input.kind,
+      value: input.value,
+    };
+  }
+}
@@ -0,0 +1,15 @@
+import { int, mysqlEnum, mysqlTable, varchar } from "drizzle-orm/mysql-core";
+import { dollar, id, timestamp, timestamps } from "../drizzle/types";
+
+export const promoTable = mysqlTable("promo", {
+  ...id,
+  ...timestamps,
+  code: varchar("code", { length: 255 }).notNull().unique(),
+  // percent takes value percent off the subtotal, amount is a balance in
+  // cents spent down by each order like a gift card
+  kind: mysqlEnum("kind", ["percent", "amount"]).notNull(),
+  value: dollar("value").notNull(),
+  maxUses: int("max_uses"),
+  uses: int("uses").notNull().default(0),
+  timeExpires: timestamp("time_expires"),
+});
@@ -56,80 +56,85 @@ export module Shippo {
     phone: z.string().optional(),
   });
 
-  export const createShipmentRate = fn(
-    z.object({
-      ounces: z.number(),
-      address: Address,
-      subtotal: z.number().int(),
-    }),
-    async (input) => {
-      const shipping = input.address;
-      const country = shipping.country.toUpperCase();
-      const international = country !== "US";
-      const shipment = await api("POST", "/shipments", {
-        address_from: TERMINAL_ADDRESS,
-        address_to: {
-          name: shipping.name,
-          street1: shipping.street1,
-          street2: shipping.street2,
-          city: shipping.city,
-          state: shipping.province,
-          country: shipping.country,
-          zip: shipping.zip,
-          phone: shipping.phone,
-        },
-        customs_declaration: international
-          ? {
-              ...CUSTOMS_DECLARATION,
-              eel_pfc: country === "CA" ? "NOEEI_30_36" : "NOEEI_30_37_a",
-              items: [
-                {
-                  ...CUSTOMS_DECLARATION_ITEM,
-                  net_weight: input.ounces,
-                  value_amount: (input.subtotal / 100).toString(),
-                },
-              ],
-            }
-          : undefined,
-        parcels: [
-          {
-            length: 12,
-            width: 12,
-            height: 12,
-            distance_unit: "in",
-            weight: input.ounces,
-            mass_unit: "oz",
-          },
-        ],
-        async: false,
-        extra: { bypass_address_validation: true },
-        // carrier_accounts: ["6c1d6acf7cc74ec5a1a4e64a5bd19107"],
-      });
+  const RateInput = z.object({
+    ounces: z.number(),
+    address: Address,
+    subtotal: z.number().int(),
+  });
 
-      console.error(JSON.stringify(shipment));
-      if (shipment.status !== "SUCCESS" || !shipment.rates?.length) {
-        throw new VisibleError(
-          "input",
-          "shipment.rate",
-          "Failed to get shipping rates.",
-        );
-      }
+  export const createShipmentRate = fn(RateInput, async (input) => {
+    const rates = await createShipmentRates(input);
+    return rates[0]!;
+  });
 
-      shipment.rates.sort(
-        (a: { amount: string }, b: { amount: string }) =>
-          Number.parseFloat(a.amount) - Number.parseFloat(b.amount),
+  // createShipmentRates returns every rate for the shipment, cheapest first.
+  // Rate IDs belong to a single shipment, so methods are picked again by
+  // shippingMethod on the next quote.
+  export const createShipmentRates = fn(RateInput, async (input) => {
+    const shipping = input.address;
+    const country = shipping.country.toUpperCase();
+    const international = country !== "US";
+    const shipment = await api("POST", "/shipments", {
+      address_from: TERMINAL_ADDRESS,
+      address_to: {
+        name: shipping.name,
+        street1: shipping.street1,
+        street2: shipping.street2,
+        city: shipping.city,
+        state: shipping.province,
+        country: shipping.country,
+        zip: shipping.zip,
+        phone: shipping.phone,
+      },
+      customs_declaration: international
+        ? {
+            ...CUSTOMS_DECLARATION,
+            eel_pfc: country === "CA" ? "NOEEI_30_36" : "NOEEI_30_37_a",
+            items: [
+              {
+                ...CUSTOMS_DECLARATION_ITEM,
+                net_weight: input.ounces,
+                value_amount: (input.subtotal / 100).toString(),
+              },
+            ],
+          }
+        : undefined,
+      parcels: [
+        {
+          length: 12,
+          width: 12,
+          height: 12,
+          distance_unit: "in",
+          weight: input.ounces,
+          mass_unit: "oz",
+        },
+      ],
+      async: false,
+      extra: { bypass_address_validation: true },
+      // carrier_accounts: ["6c1d6acf7cc74ec5a1a4e64a5bd19107"],
+    });
+
+    console.error(JSON.stringify(shipment));
+    if (shipment.status !== "SUCCESS" || !shipment.rates?.length) {
+      throw new VisibleError(
+        "input",
+        "shipment.rate",
+        "Failed to get shipping rates.",
       );
-      const rate = shipment.rates[0];
-      const shippingAmount = Number.parseFloat(rate.amount) * 100;
-      console.log(rate);
-      return {
-        shippoRateID: rate.object_id,
-        shippingAmount,
-        shippingService: `${rate.provider} ${rate.servicelevel.name}`,
-        shippingDeliveryEstimate: rate.duration_terms,
-      };
-    },
-  );
+    }
+
+    shipment.rates.sort(
+      (a: { amount: string }, b: { amount: string }) =>
+        Number.parseFloat(a.amount) - Number.parseFloat(b.amount),
+    );
+    return shipment.rates.map((rate: any) => ({
+      shippoRateID: rate.object_id as string,
+      shippingMethod: rate.servicelevel.token as string,
+      shippingAmount: Math.round(Number.parseFloat(rate.amount) * 100),
+      shippingService: `${rate.provider} ${rate.servicelevel.name}`,
+      shippingDeliveryEstimate: rate.duration_terms as string,
+    }));
+  });
 
   export const createShipment = fn(z.string(), async (orderID) => {
     const items = await useTransaction((tx) =>
@@ -12,6 +12,7 @@ export const subscriptionTable = mysqlTable(
     ...id,
     ...timestamps,
     timeNext: timestamp("time_next"),
+    timePaused: timestamp("time_paused"),
     userID: ulid("user_id")
       .references(() => userTable.id, {
         onDelete: "cascade",
@@ -44,7 +45,17 @@ export const SubscriptionFrequency = z.enum([
   "fixed",
   "daily",
   "weekly",
+  "biweekly",
   "monthly",
   "yearly",
 ]);
 export type SubscriptionFrequency = z.infer<typeof SubscriptionFrequency>;
+
+// Products that can only be bought as a subscription ship on one of these.
+// "fixed" is the original monthly schedule.
+export const SubscriptionCadences: SubscriptionFrequency[] = [
+  "fixed",
+  "weekly",
+  "biweekly",
+  "monthly",
+];
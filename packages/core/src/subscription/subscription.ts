@@ -1,13 +1,22 @@
 import { z } from "zod";
-import { SubscriptionFrequency, subscriptionTable } from "./subscription.sql";
+import {
+  SubscriptionCadences,
+  SubscriptionFrequency,
+  subscriptionTable,
+} from "./subscription.sql";
 import { useTransaction } from "../drizzle/transaction";
-import { and, eq, sql } from "drizzle-orm";
-import { useUserID } from "../actor";
+import { and, eq, inArray, isNull, lte, or, sql } from "drizzle-orm";
+import { DateTime } from "luxon";
+import { ActorContext, useUserID } from "../actor";
 import { createID } from "../util/id";
 import { fn } from "../util/fn";
 import { productTable, productVariantTable } from "../product/product.sql";
 import { Common } from "../common";
 import { Examples } from "../examples";
+import { VisibleError } from "../error";
+import { addressTable } from "../address/address.sql";
+import { cardTable } from "../card/card.sql";
+import { Order } from "../order/order";
 
 export module Subscription {
   export const Info = z
@@ -20,7 +29,7 @@ export module Subscription {
         description: "ID of the product variant being subscribed to.",
         example: Examples.Subscription.productVariantID,
       }),
-      quantity: z.number().int().openapi({
+      quantity: z.number().int().min(1).openapi({
         description: "Quantity of the subscription.",
         example: Examples.Subscription.quantity,
       }),
@@ -40,6 +49,10 @@ export module Subscription {
         description: "Next shipment and billing date for the subscription.",
         example: Examples.Subscription.next,
       }),
+      paused: z.boolean().openapi({
+        description: "Whether shipments are on hold until it's resumed.",
+        example: Examples.Subscription.paused,
+      }),
     })
     .openapi({
       ref: "Subscription",
@@ -64,12 +77,14 @@ export module Subscription {
               frequency: r.frequency,
               addressID: r.addressID,
               productVariantID: r.productVariantID,
+              next: r.timeNext ?? undefined,
+              paused: r.timePaused !== null,
             }),
           ),
         ),
     );
 
-  export const create = fn(Info.omit({ id: true }), async (input) =>
+  export const create = fn(Info.omit({ id: true, paused: true }), async (input) =>
     useTransaction(async (tx) => {
       const id = createID("subscription");
       const product = await tx
@@ -86,9 +101,12 @@ export module Subscription {
       if (!product?.subscription) {
         throw new Error("Product variant does not allow subscriptions");
       }
-      if (product.subscription === "required" && input.frequency !== "fixed") {
+      if (
+        product.subscription === "required" &&
+        !SubscriptionCadences.includes(input.frequency)
+      ) {
         throw new Error(
-          "Subscription frequency must be 'fixed' for this product",
+          "Subscription frequency must be weekly, biweekly or monthly for this product",
         );
       }
       await tx
@@ -101,6 +119,7 @@ export module Subscription {
           addressID: input.addressID,
           cardID: input.cardID,
           frequency: input.frequency,
+          timeNext: next(input.frequency, new Date()),
         })
         .onDuplicateKeyUpdate({
           set: {
@@ -108,12 +127,265 @@ export module Subscription {
             addressID: sql`VALUES(shipping_id)`,
             cardID: sql`VALUES(card_id)`,
             frequency: sql`VALUES(frequency)`,
+            timeNext: sql`VALUES(time_next)`,
+            timePaused: null,
             timeDeleted: null,
           },
         });
     }),
   );
 
+  /**
+   * The shipment after `from` for a subscription shipping at `frequency`.
+   */
+  export function next(frequency: SubscriptionFrequency, from: Date) {
+    const start = DateTime.fromJSDate(from);
+    switch (frequency) {
+      case "daily":
+        return start.plus({ days: 1 }).toJSDate();
+      case "weekly":
+        return start.plus({ weeks: 1 }).toJSDate();
+      case "biweekly":
+        return start.plus({ weeks: 2 }).toJSDate();
+      case "yearly":
+        return start.plus({ years: 1 }).toJSDate();
+      default:
+        return start.plus({ months: 1 }).toJSDate();
+    }
+  }
+
+  function get(id: string) {
+    return useTransaction(async (tx) => {
+      const row = await tx
+        .select()
+        .from(subscriptionTable)
+        .where(
+          and(
+            eq(subscriptionTable.id, id),
+            eq(subscriptionTable.userID, useUserID()),
+            isNull(subscriptionTable.timeDeleted),
+          ),
+        )
+        .then((rows) => rows.at(0));
+      if (!row)
+        throw new VisibleError(
+          "input",
+          "subscription.invalid",
+          "Subscription not found",
+        );
+      return row;
+    });
+  }
+
+  export const update = fn(
+    Info.pick({
+      id: true,
+      addressID: true,
+      cardID: true,
+      productVariantID: true,
+    }).partial({ addressID: true, cardID: true, productVariantID: true }),
+    (input) =>
+      useTransaction(async (tx) => {
+        const subscription = await get(input.id);
+        if (input.addressID) {
+          const address = await tx
+            .select({ id: addressTable.id })
+            .from(addressTable)
+            .where(
+              and(
+                eq(addressTable.id, input.addressID),
+                eq(addressTable.userID, useUserID()),
+              ),
+            )
+            .then((rows) => rows.at(0));
+          if (!address)
+            throw new VisibleError(
+              "input",
+              "subscription.address",
+              "Address not found",
+            );
+        }
+        if (input.cardID) {
+          const card = await tx
+            .select({ id: cardTable.id })
+            .from(cardTable)
+            .where(
+              and(
+                eq(cardTable.id, input.cardID),
+                eq(cardTable.userID, useUserID()),
+              ),
+            )
+            .then((rows) => rows.at(0));
+          if (!card)
+            throw new VisibleError(
+              "input",
+              "subscription.card",
+              "Card not found",
+            );
+        }
+        if (input.productVariantID) {
+          // only another variant of the same product, a different product is
+          // a different subscription
+          const variants = await tx
+            .select({
+              id: productVariantTable.id,
+              productID: productVariantTable.productID,
+            })
+            .from(productVariantTable)
+            .where(
+              inArray(productVariantTable.id, [
+                input.productVariantID,
+                subscription.productVariantID,
+              ]),
+            );
+          const variant = variants.find(
+            (v) => v.id === input.productVariantID,
+          );
+          const current = variants.find(
+            (v) => v.id === subscription.productVariantID,
+          );
+          if (!variant || variant.productID !== current?.productID)
+            throw new VisibleError(
+              "input",
+              "subscription.variant",
+              "Variant must be of the same product",
+            );
+          // a user has one subscription per variant, including deleted ones
+          const conflict = await tx
+            .select({
+              id: subscriptionTable.id,
+              timeDeleted: subscriptionTable.timeDeleted,
+            })
+            .from(subscriptionTable)
+            .where(
+              and(
+                eq(subscriptionTable.userID, useUserID()),
+                eq(subscriptionTable.productVariantID, input.productVariantID),
+              ),
+            )
+            .then((rows) => rows.at(0));
+          if (conflict && conflict.id !== input.id) {
+            if (!conflict.timeDeleted)
+              throw new VisibleError(
+                "input",
+                "subscription.variant",
+                "You already have a subscription for this variant",
+              );
+            await tx
+              .delete(subscriptionTable)
+              .where(eq(subscriptionTable.id, conflict.id));
+          }
+        }
+        await tx
+          .update(subscriptionTable)
+          .set({
+            addressID: input.addressID,
+            cardID: input.cardID,
+            productVariantID: input.productVariantID,
+          })
+          .where(eq(subscriptionTable.id, input.id));
+      }),
+  );
+
+  export const pause = fn(z.string(), (input) =>
+    useTransaction(async (tx) => {
+      await get(input);
+      await tx
+        .update(subscriptionTable)
+        .set({ timePaused: new Date() })
+        .where(
+          and(
+            eq(subscriptionTable.id, input),
+            isNull(subscriptionTable.timePaused),
+          ),
+        );
+    }),
+  );
+
+  export const resume = fn(z.string(), (input) =>
+    useTransaction(async (tx) => {
+      const subscription = await get(input);
+      if (!subscription.timePaused) return;
+      // shipments missed while paused aren't sent, start over from today
+      const now = new Date();
+      const timeNext =
+        subscription.timeNext && subscription.timeNext > now
+          ? subscription.timeNext
+          : next(subscription.frequency, now);
+      await tx
+        .update(subscriptionTable)
+        .set({ timePaused: null, timeNext })
+        .where(eq(subscriptionTable.id, input));
+    }),
+  );
+
+  export const skip = fn(z.string(), (input) =>
+    useTransaction(async (tx) => {
+      const subscription = await get(input);
+      const timeNext = next(
+        subscription.frequency,
+        subscription.timeNext ?? new Date(),
+      );
+      await tx
+        .update(subscriptionTable)
+        .set({ timeNext })
+        .where(eq(subscriptionTable.id, input));
+    }),
+  );
+
+  /**
+   * Places an order for every subscription whose next shipment is due and
+   * moves it to the shipment after. Paused and cancelled subscriptions are
+   * left alone, and one that fails to order is tried again on the next run.
+   */
+  export async function renew(now = new Date()) {
+    const due = await useTransaction((tx) =>
+      tx
+        .select()
+        .from(subscriptionTable)
+        .where(
+          and(
+            isNull(subscriptionTable.timeDeleted),
+            isNull(subscriptionTable.timePaused),
+            or(
+              isNull(subscriptionTable.timeNext),
+              lte(subscriptionTable.timeNext, now),
+            ),
+          ),
+        ),
+    );
+    for (const subscription of due) {
+      try {
+        const orderID = await ActorContext.with(
+          { type: "user", properties: { userID: subscription.userID } },
+          () =>
+            Order.create({
+              variants: {
+                [subscription.productVariantID]: subscription.quantity,
+              },
+              cardID: subscription.cardID,
+              addressID: subscription.addressID,
+            }),
+        );
+        // a run that was missed doesn't make up for it with extra shipments
+        let timeNext = next(
+          subscription.frequency,
+          subscription.timeNext ?? now,
+        );
+        if (timeNext <= now) timeNext = next(subscription.frequency, now);
+        await useTransaction((tx) =>
+          tx
+            .update(subscriptionTable)
+            .set({ timeNext })
+            .where(eq(subscriptionTable.id, subscription.id)),
+        );
+        console.log("renewed subscription", subscription.id, orderID);
+      } catch (ex) {
+        console.error("failed to renew subscription", subscription.id, ex);
+      }
+    }
+  }
+
   export const remove = fn(z.string(), (input) =>
     useTransaction(async (tx) => {
       await tx
@@ -19,6 +19,8 @@ import { subscriptionTable } from "../subscription/subscription.sql";
 import { Common } from "../common";
 import { Examples } from "../examples";
 import { addressTable } from "../address/address.sql";
+import { cardTable } from "../card/card.sql";
+import { VisibleError } from "../error";
 
 export module User {
   export const Info = z
@@ -44,6 +46,14 @@ export module User {
         description: "Stripe customer ID of the user.",
         example: Examples.User.stripeCustomerID,
       }),
+      defaultAddressID: z.string().nullable().openapi({
+        description: "ID of the address to ship to when none is chosen.",
+        example: Examples.User.defaultAddressID,
+      }),
+      defaultCardID: z.string().nullable().openapi({
+        description: "ID of the card to pay with when none is chosen.",
+        example: Examples.User.defaultCardID,
+      }),
     })
     .openapi({
       ref: "User",
@@ -100,6 +110,58 @@ export module User {
     },
   );
 
+  export const addFingerprint = fn(
+    z.object({
+      userID: Info.shape.id,
+      fingerprint: z.string(),
+    }),
+    (input) =>
+      useTransaction(async (tx) => {
+        await tx
+          .insert(userFingerprintTable)
+          .values({
+            userID: input.userID,
+            fingerprint: input.fingerprint,
+          })
+          .onDuplicateKeyUpdate({
+            set: { fingerprint: input.fingerprint },
+          });
+      }),
+  );
+
+  /**
+   * Moves a key from the MD5 fingerprint accounts were keyed by to its SHA256
+   * one, so it's listed once and removing it removes both.
+   */
+  export const migrateFingerprint = fn(
+    z.object({
+      userID: Info.shape.id,
+      legacy: z.string(),
+      fingerprint: z.string(),
+    }),
+    (input) =>
+      createTransaction(async (tx) => {
+        // keep the legacy row, it has when the key was first linked
+        await tx
+          .delete(userFingerprintTable)
+          .where(
+            and(
+              eq(userFingerprintTable.userID, input.userID),
+              eq(userFingerprintTable.fingerprint, input.fingerprint),
+            ),
+          );
+        await tx
+          .update(userFingerprintTable)
+          .set({ fingerprint: input.fingerprint })
+          .where(
+            and(
+              eq(userFingerprintTable.userID, input.userID),
+              eq(userFingerprintTable.fingerprint, input.legacy),
+            ),
+          );
+      }),
+  );
+
   export const merge = fn(z.string().array(), async (ids) => {
     const primary = ids.shift();
     if (!primary) throw new Error("No primary user");
@@ -152,12 +214,52 @@ export module User {
   });
 
   export const update = fn(
-    Info.pick({ name: true, email: true, id: true }).partial({
+    Info.pick({
+      name: true,
+      email: true,
+      id: true,
+      defaultAddressID: true,
+      defaultCardID: true,
+    }).partial({
       name: true,
       email: true,
+      defaultAddressID: true,
+      defaultCardID: true,
     }),
     (input) =>
       useTransaction(async (tx) => {
+        if (input.defaultAddressID) {
+          const address = await tx
+            .select({ id: addressTable.id })
+            .from(addressTable)
+            .where(
+              and(
+                eq(addressTable.id, input.defaultAddressID),
+                eq(addressTable.userID, input.id),
+              ),
+            )
+            .then((rows) => rows.at(0));
+          if (!address)
+            throw new VisibleError(
+              "input",
+              "user.default_address",
+              "Address not found",
+            );
+        }
+        if (input.defaultCardID) {
+          const card = await tx
+            .select({ id: cardTable.id })
+            .from(cardTable)
+            .where(
+              and(
+                eq(cardTable.id, input.defaultCardID),
+                eq(cardTable.userID, input.id),
+              ),
+            )
+            .then((rows) => rows.at(0));
+          if (!card)
+            throw new VisibleError("input", "user.default_card", "Card not found");
+        }
         await afterTx(() =>
           bus.publish(Resource.Bus, Events.Updated, {
             userID: input.id,
@@ -168,6 +270,8 @@ export module User {
           .set({
             name: input.name,
             email: input.email,
+            defaultAddressID: input.defaultAddressID,
+            defaultCardID: input.defaultCardID,
           })
           .where(eq(userTable.id, input.id));
       }),
@@ -222,6 +326,8 @@ export module User {
       email: input.email,
       fingerprint: input.fingerprint,
       stripeCustomerID: input.stripeCustomerID,
+      defaultAddressID: input.defaultAddressID,
+      defaultCardID: input.defaultCardID,
     };
   }
 }
@@ -24,6 +24,8 @@ export const userTable = mysqlTable("user", {
     .notNull(),
   emailOctopusID: text("email_octopus_id"),
   flags: json("flags").$type<UserFlags>().default({}),
+  defaultAddressID: ulid("default_address_id"),
+  defaultCardID: ulid("default_card_id"),
 });
 
 export const userFingerprintTable = mysqlTable(
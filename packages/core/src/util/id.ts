@@ -15,6 +15,7 @@ export const prefixes = {
   apiClient: "cli",
   apiSecret: "sec",
   apiPersonal: "pat",
+  promo: "prm",
 } as const;
 
 export function createID(prefix: keyof typeof prefixes): string {
@@ -0,0 +1,104 @@
+import { describe, expect } from "bun:test";
+import { DateTime } from "luxon";
+import { withTestUser } from "./util";
+import { db, eq } from "../src/drizzle/index";
+import { Product } from "../src/product/index";
+import { productTable } from "../src/product/product.sql";
+import { createID } from "../src/util/id";
+import { Card } from "../src/card";
+import { Address } from "../src/address";
+import { Order } from "../src/order/order";
+import { Subscription } from "../src/subscription/subscription";
+import { SubscriptionFrequency } from "../src/subscription/subscription.sql";
+
+describe("subscription", async () => {
+  const productID = await Product.create({
+    id: createID("product"),
+    name: "test-product",
+    description: "",
+  });
+  await db
+    .update(productTable)
+    .set({ subscription: "allowed" })
+    .where(eq(productTable.id, productID));
+  const variants = await Promise.all(
+    ["whole", "ground"].map((name) =>
+      Product.addVariant({ productID, name, price: 2200 }),
+    ),
+  );
+
+  async function subscribe(
+    productVariantID: string,
+    frequency: SubscriptionFrequency = "weekly",
+  ) {
+    const cardID = await Card.create({ token: "tok_visa" });
+    const addressID = await Address.create({
+      name: "John Smith",
+      zip: "33133",
+      city: "Miami",
+      country: "US",
+      street1: "2800 SW 28th Terrace",
+      province: "FL",
+    });
+    await Subscription.create({
+      productVariantID,
+      quantity: 2,
+      addressID,
+      cardID,
+      frequency,
+    });
+    return Subscription.list().then((rows) =>
+      rows.find((row) => row.productVariantID === productVariantID),
+    );
+  }
+
+  withTestUser("renew", async () => {
+    const active = await subscribe(variants[0]!);
+    const paused = await subscribe(variants[1]!);
+    await Subscription.pause(paused!.id);
+
+    const now = DateTime.fromJSDate(active!.next!).plus({ hours: 1 });
+    await Subscription.renew(now.toJSDate());
+
+    const orders = await Order.list();
+    expect(orders).toHaveLength(1);
+    expect(orders[0]!.items[0]!.productVariantID).toEqual(variants[0]);
+    expect(orders[0]!.items[0]!.quantity).toEqual(2);
+
+    const after = await Subscription.list();
+    const renewed = after.find((row) => row.id === active!.id);
+    expect(renewed!.next).toEqual(
+      DateTime.fromJSDate(active!.next!).plus({ weeks: 1 }).toJSDate(),
+    );
+    const held = after.find((row) => row.id === paused!.id);
+    expect(held!.next).toEqual(paused!.next);
+  });
+
+  withTestUser("biweekly", async () => {
+    // the Go clients send "biweekly" ahead of the SDK's frequency enum
+    expect(
+      Subscription.create.schema.parse({
+        productVariantID: variants[0],
+        quantity: 1,
+        addressID: "shp_test",
+        cardID: "crd_test",
+        frequency: "biweekly",
+      }).frequency,
+    ).toEqual("biweekly");
+
+    const before = new Date();
+    const subscription = await subscribe(variants[0]!, "biweekly");
+    expect(subscription!.frequency).toEqual("biweekly");
+    const weeks = DateTime.fromJSDate(subscription!.next!)
+      .diff(DateTime.fromJSDate(before), "weeks")
+      .as("weeks");
+    expect(Math.round(weeks)).toEqual(2);
+
+    const now = DateTime.fromJSDate(subscription!.next!).plus({ hours: 1 });
+    await Subscription.renew(now.toJSDate());
+    const renewed = await Subscription.list().then((rows) => rows[0]);
+    expect(renewed!.next).toEqual(
+      DateTime.fromJSDate(subscription!.next!).plus({ weeks: 2 }).toJSDate(),
+    );
+  });
+});
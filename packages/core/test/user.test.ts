@@ -1,4 +1,5 @@
 import { describe, it, expect } from "bun:test";
+import { nanoid } from "nanoid/non-secure";
 import { User } from "../src/user";
 
 describe("user", () => {
@@ -9,4 +10,16 @@ describe("user", () => {
     expect(await User.fromID(user)).toBeDefined();
     expect(await User.fromFingerprint("test")).toBeDefined();
   });
+
+  it("migrateFingerprint", async () => {
+    const legacy = "test+md5+" + nanoid();
+    const fingerprint = "test+sha256+" + nanoid();
+    const user = await User.create({ fingerprint: legacy });
+    // linked alongside the legacy row before keys were migrated
+    await User.addFingerprint({ userID: user, fingerprint });
+
+    await User.migrateFingerprint({ userID: user, legacy, fingerprint });
+    expect(await User.fromFingerprint(legacy)).toBeUndefined();
+    expect((await User.fromFingerprint(fingerprint))?.id).toEqual(user);
+  });
 });
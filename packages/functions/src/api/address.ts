@@ -70,6 +70,45 @@ export module AddressApi {
         return c.json({ data: addressID }, 200);
       },
     )
+    .put(
+      "/:id",
+      describeRoute({
+        tags: ["Address"],
+        summary: "Update address",
+        description: "Update a shipping address of the current user.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Shipping address was updated successfully.",
+          },
+        },
+      }),
+      validator(
+        "param",
+        z.object({
+          id: Address.Info.shape.id.openapi({
+            description: "ID of the shipping address to update.",
+            example: Examples.Shipping.id,
+          }),
+        }),
+      ),
+      validator(
+        "json",
+        Address.Inner.openapi({
+          description: "Address information.",
+          example: Examples.Address,
+        }),
+      ),
+      async (c) => {
+        const param = c.req.valid("param");
+        await Address.update({ id: param.id, ...c.req.valid("json") });
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
     .delete(
       "/:id",
       describeRoute({
@@ -8,6 +8,7 @@ import { Card } from "@terminal/core/card/index";
 import { Examples } from "@terminal/core/examples";
 import { Address } from "@terminal/core/address/index";
 import { Order } from "@terminal/core/order/order";
+import { Promo } from "@terminal/core/promo/index";
 
 export module CartApi {
   export const route = new Hono()
@@ -116,6 +117,66 @@ export module CartApi {
         return c.json({ data: "ok" as const }, 200);
       },
     )
+    .get(
+      "/shipping",
+      describeRoute({
+        tags: ["Cart"],
+        summary: "List shipping rates",
+        description:
+          "List the shipping methods available for the current user's cart, cheapest first.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(
+                  Cart.ShippingRate.array().openapi({
+                    description: "Shipping methods and their rates.",
+                    example: [Examples.ShippingRate],
+                  }),
+                ),
+              },
+            },
+            description: "Shipping methods and their rates.",
+          },
+        },
+      }),
+      async (c) => {
+        return c.json({ data: await Cart.listShipping() }, 200);
+      },
+    )
+    .put(
+      "/shipping",
+      describeRoute({
+        tags: ["Cart"],
+        summary: "Set shipping method",
+        description: "Set the shipping method for the current user's cart.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Shipping method was set successfully.",
+          },
+        },
+      }),
+      validator(
+        "json",
+        z.object({
+          shippingRateID: Cart.ShippingRate.shape.id.openapi({
+            description:
+              "ID of the shipping method to set for the current user's cart.",
+            example: Examples.ShippingRate.id,
+          }),
+        }),
+      ),
+      async (c) => {
+        const body = c.req.valid("json");
+        await Cart.setShipping(body.shippingRateID);
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
     .put(
       "/card",
       describeRoute({
@@ -149,6 +210,65 @@ export module CartApi {
         return c.json({ data: "ok" as const }, 200);
       },
     )
+    .put(
+      "/promo",
+      describeRoute({
+        tags: ["Cart"],
+        summary: "Set promo",
+        description: "Apply a promo or gift code to the current user's cart.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(
+                  Promo.Info.openapi({
+                    description: "The applied promo.",
+                    example: Examples.Promo,
+                  }),
+                ),
+              },
+            },
+            description: "Promo was applied successfully.",
+          },
+        },
+      }),
+      validator(
+        "json",
+        z.object({
+          code: Promo.Info.shape.code.openapi({
+            description:
+              "Promo or gift code to apply to the current user's cart.",
+            example: Examples.Promo.code,
+          }),
+        }),
+      ),
+      async (c) => {
+        const body = c.req.valid("json");
+        return c.json({ data: await Cart.setPromo(body.code) }, 200);
+      },
+    )
+    .delete(
+      "/promo",
+      describeRoute({
+        tags: ["Cart"],
+        summary: "Remove promo",
+        description: "Remove the promo code from the current user's cart.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Promo was removed successfully.",
+          },
+        },
+      }),
+      async (c) => {
+        await Cart.removePromo();
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
     .post(
       "/convert",
       describeRoute({
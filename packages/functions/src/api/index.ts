@@ -24,6 +24,7 @@ import { ProfileApi } from "./profile";
 import { ViewApi } from "./view";
 import { AppApi } from "./app";
 import { TokenApi } from "./token";
+import { KeyApi } from "./key";
 import { FilterContext } from "@terminal/core/product/filter";
 
 const client = createClient({
@@ -49,6 +50,12 @@ const auth: MiddlewareHandler = async (c, next) => {
       const token = await Api.Personal.fromToken(bearerToken);
       if (!token)
         throw new VisibleError("auth", "auth.invalid", "Invalid bearer token");
+      if (!Api.Personal.allows(token.scope, c.req.method, c.req.path))
+        throw new VisibleError(
+          "auth",
+          "auth.scope",
+          `This token's ${token.scope} scope can't ${c.req.method} ${c.req.path}`,
+        );
       return ActorContext.with(
         {
           type: "user",
@@ -115,6 +122,7 @@ const routes = app
   .route("/order", OrderApi.route)
   .route("/subscription", SubscriptionApi.route)
   .route("/token", TokenApi.route)
+  .route("/key", KeyApi.route)
   .route("/app", AppApi.route)
   .route("/view", ViewApi.route)
   .route("/email", EmailApi.route)
@@ -0,0 +1,99 @@
+import { z } from "zod";
+import { Result } from "./common";
+import { Hono } from "hono";
+import { describeRoute } from "hono-openapi";
+import { validator } from "hono-openapi/zod";
+import { Examples } from "@terminal/core/examples";
+import { Key } from "@terminal/core/key/index";
+
+export module KeyApi {
+  export const route = new Hono()
+    .get(
+      "/",
+      describeRoute({
+        tags: ["Key"],
+        summary: "List keys",
+        description: "List the SSH keys linked to the current user.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(
+                  Key.Info.array().openapi({
+                    description: "List of SSH keys.",
+                    example: [Examples.Key],
+                  }),
+                ),
+              },
+            },
+            description: "List of SSH keys.",
+          },
+        },
+      }),
+      async (c) => {
+        const keys = await Key.list();
+        return c.json({ data: keys }, 200);
+      },
+    )
+    .post(
+      "/pair",
+      describeRoute({
+        tags: ["Key"],
+        summary: "Pair key",
+        description:
+          "Create a short-lived code that links another SSH key to the current user when entered from a session signed in with it.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(
+                  Key.Pairing.openapi({
+                    description: "Pairing code.",
+                    example: Examples.KeyPairing,
+                  }),
+                ),
+              },
+            },
+            description: "Pairing code.",
+          },
+        },
+      }),
+      async (c) => {
+        const pairing = await Key.pair();
+        return c.json({ data: pairing }, 200);
+      },
+    )
+    .delete(
+      "/:id",
+      describeRoute({
+        tags: ["Key"],
+        summary: "Delete key",
+        description:
+          "Unlink the SSH key with the given ID. The last key can't be removed.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "SSH key was unlinked successfully.",
+          },
+        },
+      }),
+      validator(
+        "param",
+        z.object({
+          id: Key.Info.shape.id.openapi({
+            description: "ID of the SSH key to unlink.",
+            example: Examples.Key.id,
+          }),
+        }),
+      ),
+      async (c) => {
+        const param = c.req.valid("param");
+        await Key.remove(param.id);
+        return c.json({ data: "ok" as const }, 200);
+      },
+    );
+}
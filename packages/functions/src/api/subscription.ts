@@ -59,10 +59,15 @@ export module SubscriptionApi {
       }),
       validator(
         "json",
-        Subscription.Info.omit({ id: true }).openapi({
+        Subscription.create.schema.openapi({
           description: "Subscription information.",
           // @ts-ignore
-          example: { ...Examples.Subscription, id: undefined, next: undefined },
+          example: {
+            ...Examples.Subscription,
+            id: undefined,
+            next: undefined,
+            paused: undefined,
+          },
         }),
       ),
       async (c) => {
@@ -71,6 +76,142 @@ export module SubscriptionApi {
         return c.json({ data: "ok" as const }, 200);
       },
     )
+    .put(
+      "/:id",
+      describeRoute({
+        tags: ["Subscription"],
+        summary: "Update",
+        description:
+          "Change the address, card or variant of a subscription for the current user.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Subscription was updated successfully.",
+          },
+        },
+      }),
+      validator(
+        "param",
+        z.object({
+          id: Subscription.Info.shape.id.openapi({
+            description: "ID of the subscription to update.",
+            example: Examples.Subscription.id,
+          }),
+        }),
+      ),
+      validator(
+        "json",
+        Subscription.update.schema.omit({ id: true }).openapi({
+          description: "Fields of the subscription to change.",
+          example: { addressID: Examples.Subscription.addressID },
+        }),
+      ),
+      async (c) => {
+        const param = c.req.valid("param");
+        await Subscription.update({ id: param.id, ...c.req.valid("json") });
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
+    .post(
+      "/:id/pause",
+      describeRoute({
+        tags: ["Subscription"],
+        summary: "Pause",
+        description: "Hold shipments of a subscription for the current user until it's resumed.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Subscription was paused successfully.",
+          },
+        },
+      }),
+      validator(
+        "param",
+        z.object({
+          id: Subscription.Info.shape.id.openapi({
+            description: "ID of the subscription to pause.",
+            example: Examples.Subscription.id,
+          }),
+        }),
+      ),
+      async (c) => {
+        const param = c.req.valid("param");
+        await Subscription.pause(param.id);
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
+    .post(
+      "/:id/resume",
+      describeRoute({
+        tags: ["Subscription"],
+        summary: "Resume",
+        description: "Resume shipments of a paused subscription for the current user.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Subscription was resumed successfully.",
+          },
+        },
+      }),
+      validator(
+        "param",
+        z.object({
+          id: Subscription.Info.shape.id.openapi({
+            description: "ID of the subscription to resume.",
+            example: Examples.Subscription.id,
+          }),
+        }),
+      ),
+      async (c) => {
+        const param = c.req.valid("param");
+        await Subscription.resume(param.id);
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
+    .post(
+      "/:id/skip",
+      describeRoute({
+        tags: ["Subscription"],
+        summary: "Skip",
+        description: "Skip the next shipment of a subscription for the current user.",
+        responses: {
+          200: {
+            content: {
+              "application/json": {
+                schema: Result(z.literal("ok")),
+              },
+            },
+            description: "Next shipment was skipped successfully.",
+          },
+        },
+      }),
+      validator(
+        "param",
+        z.object({
+          id: Subscription.Info.shape.id.openapi({
+            description: "ID of the subscription to skip.",
+            example: Examples.Subscription.id,
+          }),
+        }),
+      ),
+      async (c) => {
+        const param = c.req.valid("param");
+        await Subscription.skip(param.id);
+        return c.json({ data: "ok" as const }, 200);
+      },
+    )
     .delete(
       "/:id",
       describeRoute({
@@ -110,8 +110,20 @@ export module TokenApi {
           },
         },
       }),
+      validator(
+        "json",
+        Api.Personal.create.schema.openapi({
+          description:
+            "Optional label, scope and expiry. Tokens have full access and never expire by default.",
+          example: {
+            label: Examples.Token.label,
+            scope: Examples.Token.scope,
+            expires: Examples.Token.time.expires,
+          },
+        }),
+      ),
       async (c) => {
-        const token = await Api.Personal.create();
+        const token = await Api.Personal.create(c.req.valid("json"));
         return c.json({ data: token }, 200);
       },
     )
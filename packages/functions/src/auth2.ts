@@ -12,8 +12,11 @@ import { THEME_TERMINAL } from "@openauthjs/openauth/ui/theme";
 import { Resource } from "sst";
 import { handle } from "hono/aws-lambda";
 import { User } from "@terminal/core/user/index";
+import { Key } from "@terminal/core/key/index";
 import { Api } from "@terminal/core/api/api";
 import { Email } from "@terminal/core/email/index";
+import { Auth } from "@terminal/core/auth/index";
+import { VisibleError } from "@terminal/core/error";
 import { logger } from "hono/logger";
 
 const app = issuer({
@@ -72,17 +75,47 @@ const app = issuer({
         if (input.clientSecret !== Resource.AuthFingerprintKey.value) {
           throw new Error("Invalid authorization token");
         }
+        const email = input.params.email?.trim().toLowerCase();
+        if (email) {
+          const valid =
+            input.params.code &&
+            (await Auth.verifyCode({ email, code: input.params.code }));
+          if (!valid) {
+            throw new Error("Invalid code");
+          }
+          return {
+            email,
+          };
+        }
         const fingerprint = input.params.fingerprint;
         if (!fingerprint) {
           throw new Error("Fingerprint is required");
         }
+        // a code from another session to link this key to its account
+        let paired: string | undefined;
+        if (input.params.pairing_code) {
+          paired = await Key.claim(input.params.pairing_code);
+          if (!paired) {
+            throw new Error("Invalid pairing code");
+          }
+        }
         return {
           fingerprint,
+          // MD5 fingerprint of the same key, accounts created before the
+          // switch to SHA256 are still keyed by it
+          legacy: input.params.legacy_fingerprint || undefined,
+          paired,
+          // claim codes sign back in to an account, they never create one
+          existing: input.params.existing === "true",
         };
       },
       init() {},
     } as Provider<{
-      fingerprint: string;
+      fingerprint?: string;
+      legacy?: string;
+      paired?: string;
+      existing?: boolean;
+      email?: string;
     }>,
   },
   allow: async (input) => {
@@ -101,8 +134,45 @@ const app = issuer({
     return false;
   },
   success: async (ctx, value) => {
-    if (value.provider === "ssh") {
+    if (value.provider === "ssh" && value.fingerprint && value.paired) {
+      // move the key, and whatever its account had, to the paired account
+      const current = await User.fromFingerprint(value.fingerprint);
+      if (current && current.id !== value.paired) {
+        await User.merge([value.paired, current.id]);
+      }
+      if (!current) {
+        await User.addFingerprint({
+          userID: value.paired,
+          fingerprint: value.fingerprint,
+        });
+      }
+      return ctx.subject("user", {
+        userID: value.paired,
+      });
+    }
+
+    if (value.provider === "ssh" && value.fingerprint) {
       let id = await User.fromFingerprint(value.fingerprint).then((x) => x?.id);
+      if (value.legacy) {
+        // the key signs in by SHA256 from now on, a row left behind for its
+        // MD5 fingerprint would show up as a second key
+        const legacy = await User.fromFingerprint(value.legacy).then(
+          (x) => x?.id,
+        );
+        if (legacy && (!id || id === legacy)) {
+          await User.migrateFingerprint({
+            userID: legacy,
+            legacy: value.legacy,
+            fingerprint: value.fingerprint,
+          });
+          id = legacy;
+        }
+      }
+      if (!id && value.existing) {
+        return new Response("no account with that fingerprint", {
+          status: 404,
+        });
+      }
       if (!id) {
         id = await User.create({
           fingerprint: value.fingerprint,
@@ -114,6 +184,9 @@ const app = issuer({
     }
 
     let email = undefined as string | undefined;
+    if (value.provider === "ssh") {
+      email = value.email;
+    }
     if (value.provider === "code") {
       email = value.claims.email;
     }
@@ -168,5 +241,29 @@ const app = issuer({
   },
 }).use(logger());
 
+// Sends a one-time code to sign in to an SSH session without a key, the SSH
+// server exchanges it with the ssh provider.
+app.post("/ssh/code", async (c) => {
+  const form = await c.req.formData();
+  if (form.get("client_secret") !== Resource.AuthFingerprintKey.value) {
+    return c.json({ error: "unauthorized_client" }, 401);
+  }
+  const email = form.get("email")?.toString().trim().toLowerCase();
+  if (!email) {
+    return c.json({ error: "invalid_request" }, 400);
+  }
+  // the SSH server makes the request, it passes on the address of the
+  // session asking for the code
+  const ip = form.get("remote_ip")?.toString() || undefined;
+  try {
+    await Auth.sendCode({ email, ip });
+  } catch (ex) {
+    if (ex instanceof VisibleError)
+      return c.json({ error: "slow_down", error_description: ex.message }, 429);
+    throw ex;
+  }
+  return c.json({});
+});
+
 // @ts-ignore
 export const handler = handle(app);
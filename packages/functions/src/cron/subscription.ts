@@ -0,0 +1,5 @@
+import { Subscription } from "@terminal/core/subscription/subscription";
+
+export const handler = async () => {
+  await Subscription.renew();
+};
@@ -1,26 +1,106 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/command"
+	"github.com/terminaldotshop/terminal/go/pkg/output"
+	"github.com/terminaldotshop/terminal/go/pkg/resource"
 	"github.com/terminaldotshop/terminal/go/pkg/tui"
 )
 
 func main() {
-	log, err := os.Create("output.log")
+	fingerprint := flag.String(
+		"fingerprint",
+		getEnv("TERMINAL_FINGERPRINT", "fingerprint"),
+		"fingerprint used to sign in (env TERMINAL_FINGERPRINT)",
+	)
+	config := flag.String(
+		"config",
+		os.Getenv("TERMINAL_CONFIG"),
+		"TOML or JSON config file (env TERMINAL_CONFIG)",
+	)
+	logPath := flag.String(
+		"log",
+		os.Getenv("TERMINAL_LOG"),
+		"file to write logs to (env TERMINAL_LOG)",
+	)
+	format := flag.String(
+		"output",
+		"text",
+		"output format for commands: text or json",
+	)
+	flag.Usage = usage
+	flag.Parse()
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "error: unknown output format %q\n", *format)
+		os.Exit(2)
+	}
+
+	err := resource.Load(resource.Options{
+		File:     *config,
+		Required: []string{"AuthFingerprintKey.value", "StripePublic.value"},
+	})
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
-	defer log.Close()
-	slog.SetDefault(slog.New(slog.NewTextHandler(log, &slog.HandlerOptions{})))
 
+	logger, err := newLogger(*logPath, *fingerprint)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	if flag.NArg() == 0 {
+		runTUI(api.Identity{Fingerprint: *fingerprint}, logger)
+		return
+	}
+
+	cmd, args := command.Find(flag.Args())
+	if cmd == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	client, _, err := api.NewUserClient(api.Identity{Fingerprint: *fingerprint}, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	err = cmd.Run(
+		context.Background(),
+		command.Context{Client: client, Out: os.Stdout, Err: os.Stderr, JSON: *format == "json"},
+		args,
+	)
+	if err != nil {
+		if *format == "json" {
+			output.Write(os.Stderr, "error", output.Error{Message: api.GetErrorMessage(err)})
+		} else {
+			fmt.Fprintln(os.Stderr, "error:", api.GetErrorMessage(err))
+		}
+		os.Exit(1)
+	}
+}
+
+func runTUI(identity api.Identity, logger *slog.Logger) {
 	model, err := tui.NewModel(
 		lipgloss.DefaultRenderer(),
-		"fingerprint",
+		identity,
+		logger,
+		nil,
+		0,
 	)
 	if err != nil {
 		panic(err)
@@ -30,3 +110,43 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newLogger returns a logger tagged with a fresh session ID, writing to path
+// or nowhere when path is empty. stdout and stderr are taken by the TUI and
+// command output. The file stays open until the process exits.
+func newLogger(path string, fingerprint string) (*slog.Logger, error) {
+	var w io.Writer = io.Discard
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		w = file
+	}
+
+	logger := slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{})).With(
+		"session", uuid.NewString(),
+		"fingerprint", fingerprint,
+	)
+	return logger, nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: terminal [flags] [command]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "without a command the interactive shop is started.")
+	fmt.Fprintln(os.Stderr, "")
+	command.PrintUsage(os.Stderr)
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "flags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "the json document schema is described in pkg/output.")
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
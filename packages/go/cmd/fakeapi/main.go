@@ -0,0 +1,50 @@
+package main
+
+// A fake Terminal API for working offline. Start it, export the printed
+// environment and run the cli or the ssh server as usual:
+//
+//	go run ./cmd/fakeapi
+//	eval "$(go run ./cmd/fakeapi -env)"
+//	go run ./cmd/cli
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/terminaldotshop/terminal/go/pkg/fakeapi"
+)
+
+func main() {
+	port := flag.String("port", "4010", "port to listen on")
+	seedPath := flag.String("seed", "", "JSON file with seed fixtures (defaults to the bundled ones)")
+	env := flag.Bool("env", false, "print the environment for the other binaries and exit")
+	flag.Parse()
+
+	url := "http://" + net.JoinHostPort("localhost", *port)
+	if *env {
+		fmt.Printf("export SST_RESOURCE_Api='{\"url\":\"%s\"}'\n", url)
+		fmt.Printf("export SST_RESOURCE_Auth='{\"url\":\"%s/auth\"}'\n", url)
+		fmt.Printf("export SST_RESOURCE_AuthFingerprintKey='{\"value\":\"fake\"}'\n")
+		return
+	}
+
+	seed := fakeapi.DefaultSeed()
+	if *seedPath != "" {
+		var err error
+		seed, err = fakeapi.LoadSeed(*seedPath)
+		if err != nil {
+			log.Fatal("Could not load seed", "path", *seedPath, "error", err)
+		}
+	}
+
+	log.Info("Starting fake API", "url", url)
+	err := http.ListenAndServe(net.JoinHostPort("", *port), fakeapi.New(seed))
+	if err != nil {
+		log.Error("ListenAndServe error", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/terminaldotshop/terminal/go/pkg/tui"
+)
+
+// programs keeps track of the running shops so they can be told when the
+// server starts draining.
+type programs struct {
+	mu          sync.Mutex
+	running     map[*tea.Program]struct{}
+	draining    bool
+	idleTimeout time.Duration
+}
+
+func newPrograms(idleTimeout time.Duration) *programs {
+	return &programs{
+		running:     map[*tea.Program]struct{}{},
+		idleTimeout: idleTimeout,
+	}
+}
+
+// handler is a bubbletea.ProgramHandler that registers the program for the
+// lifetime of the session.
+func (p *programs) handler(s ssh.Session) *tea.Program {
+	model, options := teaHandler(s, p.idleTimeout)
+	if model == nil {
+		return nil
+	}
+	program := tea.NewProgram(model, append(options, bubbletea.MakeOptions(s)...)...)
+
+	p.mu.Lock()
+	p.running[program] = struct{}{}
+	if p.draining {
+		// connected just before the listener closed
+		go program.Send(tui.DrainMsg{})
+	}
+	p.mu.Unlock()
+
+	go func() {
+		<-s.Context().Done()
+		p.mu.Lock()
+		delete(p.running, program)
+		p.mu.Unlock()
+	}()
+
+	return program
+}
+
+// drain shows the restart banner in every session. Sessions close themselves
+// unless they're placing an order.
+func (p *programs) drain() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.draining = true
+	for program := range p.running {
+		// Send blocks until the program reads it
+		go program.Send(tui.DrainMsg{})
+	}
+	return len(p.running)
+}
+
+func (p *programs) isDraining() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.draining
+}
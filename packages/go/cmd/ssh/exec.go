@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/command"
+	"github.com/terminaldotshop/terminal/go/pkg/output"
+)
+
+// commandMiddleware serves `ssh terminal.shop <command>` without requiring a
+// PTY. Sessions without a command fall through to the TUI.
+func commandMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if len(s.Command()) == 0 {
+				next(s)
+				return
+			}
+			s.Exit(runCommand(s))
+		}
+	}
+}
+
+func runCommand(s ssh.Session) int {
+	flags := flag.NewFlagSet("ssh", flag.ContinueOnError)
+	flags.SetOutput(s.Stderr())
+	format := flags.String("output", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintln(s.Stderr(), "usage: ssh terminal.shop [-output json] <command>")
+		fmt.Fprintln(s.Stderr(), "")
+		command.PrintUsage(s.Stderr())
+	}
+	if err := flags.Parse(s.Command()); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(s.Stderr(), "unknown output format %q\n", *format)
+		return 2
+	}
+	if flags.Arg(0) == "help" {
+		flags.Usage()
+		return 0
+	}
+
+	cmd, args := command.Find(flags.Args())
+	if cmd == nil {
+		flags.Usage()
+		return 2
+	}
+
+	logger := sessionLogger(s)
+	logger.Info("running command", "command", cmd.Name)
+	client, _, err := api.NewUserClient(identity(s.Context()), logger)
+	if err != nil {
+		fmt.Fprintln(s.Stderr(), "could not sign in, try again later")
+		return 1
+	}
+
+	err = cmd.Run(
+		s.Context(),
+		command.Context{Client: client, Out: s, Err: s.Stderr(), JSON: *format == "json"},
+		args,
+	)
+	if err != nil {
+		message := api.GetErrorMessage(err)
+		if *format == "json" {
+			output.Write(s.Stderr(), "error", output.Error{Message: message})
+		} else {
+			fmt.Fprintln(s.Stderr(), "error:", message)
+		}
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/md5"
+	"crypto/rsa"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// keyIdentity identifies a session by the SHA256 fingerprint of its key,
+// which is what OpenSSH shows by default, and keeps the MD5 fingerprint
+// accounts were created with so they still resolve.
+func keyIdentity(key ssh.PublicKey) api.Identity {
+	hash := md5.Sum(key.Marshal())
+	fingerprint := gossh.FingerprintSHA256(key)
+	return api.Identity{
+		Fingerprint: fingerprint,
+		Legacy:      hex.EncodeToString(hash[:]),
+		Key:         fmt.Sprintf("%d %s (%s)", keyBits(key), fingerprint, keyType(key)),
+	}
+}
+
+// identity returns the identity set during authentication.
+func identity(ctx ssh.Context) api.Identity {
+	identity, _ := ctx.Value("identity").(api.Identity)
+	return identity
+}
+
+// keyBits returns the key size the way ssh-keygen -l reports it.
+func keyBits(key ssh.PublicKey) int {
+	if crypto, ok := key.(gossh.CryptoPublicKey); ok {
+		switch public := crypto.CryptoPublicKey().(type) {
+		case *rsa.PublicKey:
+			return public.N.BitLen()
+		case *ecdsa.PublicKey:
+			return public.Curve.Params().BitSize
+		}
+	}
+	switch key.Type() {
+	case gossh.KeyAlgoSKECDSA256:
+		return 256
+	case gossh.KeyAlgoDSA:
+		return 1024
+	}
+	// ed25519 and sk-ed25519
+	return 256
+}
+
+// keyType returns the key type the way ssh-keygen -l reports it.
+func keyType(key ssh.PublicKey) string {
+	switch key.Type() {
+	case gossh.KeyAlgoRSA:
+		return "RSA"
+	case gossh.KeyAlgoDSA:
+		return "DSA"
+	case gossh.KeyAlgoECDSA256, gossh.KeyAlgoECDSA384, gossh.KeyAlgoECDSA521:
+		return "ECDSA"
+	case gossh.KeyAlgoSKECDSA256:
+		return "ECDSA-SK"
+	case gossh.KeyAlgoED25519:
+		return "ED25519"
+	case gossh.KeyAlgoSKED25519:
+		return "ED25519-SK"
+	}
+	return key.Type()
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestKeyIdentity(t *testing.T) {
+	// expected values from ssh-keygen -lf and ssh-keygen -l -E md5 -f
+	authorized := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKBZyWFFbJNqZUazO+Xf8LyfOkfGHKoj1S1Wa+mJsbjg"
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorized))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := api.Identity{
+		Fingerprint: "SHA256:vBBsZ9l3GS35eVLQeVJ4Li0e3i/E7+fCcIQb2M1kGt0",
+		Legacy:      "bb5e319666b5ba57b180e5a67eeeb36d",
+		Key:         "256 SHA256:vBBsZ9l3GS35eVLQeVJ4Li0e3i/E7+fCcIQb2M1kGt0 (ED25519)",
+	}
+	if got := keyIdentity(key); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/terminaldotshop/terminal/go/pkg/metrics"
+)
+
+// limits caps how much of the shop a single client can use. Keys are
+// accepted without question and keyboard-interactive sign in hands out a
+// fresh fingerprint every time, so the source address is what keeps one
+// client from opening unlimited sessions. Zero disables a limit.
+type limits struct {
+	// concurrent sessions per source IP
+	sessionsPerIP int
+	// new sessions per source IP per minute
+	newSessionsPerIP int
+	// token fetches per fingerprint per minute, every session signs in once
+	tokensPerFingerprint int
+	// email codes sent or checked per source IP per minute, during
+	// keyboard-interactive sign in
+	signInsPerIP int
+}
+
+// loadLimits reads the limits from the environment, falling back to
+// defaults that leave room for a few shoppers behind the same NAT.
+func loadLimits() limits {
+	return limits{
+		sessionsPerIP:        envLimit("MAX_SESSIONS_PER_IP", 10),
+		newSessionsPerIP:     envLimit("MAX_NEW_SESSIONS_PER_MINUTE", 30),
+		tokensPerFingerprint: envLimit("MAX_TOKEN_FETCHES_PER_MINUTE", 10),
+		signInsPerIP:         envLimit("MAX_SIGN_INS_PER_MINUTE", 10),
+	}
+}
+
+func envLimit(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Fatal("Invalid "+name, "value", value)
+	}
+	return limit
+}
+
+type limiter struct {
+	limits      limits
+	mu          sync.Mutex
+	open        map[string]int
+	newSessions *window
+	tokens      *window
+	signIns     *window
+}
+
+func newLimiter(limits limits) *limiter {
+	return &limiter{
+		limits:      limits,
+		open:        map[string]int{},
+		newSessions: newWindow(time.Minute),
+		tokens:      newWindow(time.Minute),
+		signIns:     newWindow(time.Minute),
+	}
+}
+
+// rejection is what a client is told when a limit is hit, and why in the
+// logs and metrics.
+type rejection struct {
+	reason  string
+	message string
+}
+
+var (
+	tooManySessions = rejection{
+		reason:  "sessions_per_ip",
+		message: "Too many shops open from your address. Close one and try again.",
+	}
+	tooManyNewSessions = rejection{
+		reason:  "new_sessions_per_ip",
+		message: "You're connecting a little too often. Try again in a minute.",
+	}
+	tooManyTokens = rejection{
+		reason:  "tokens_per_fingerprint",
+		message: "You're signing in a little too often. Try again in a minute.",
+	}
+	tooManySignIns = rejection{
+		reason:  "sign_ins_per_ip",
+		message: "Too many sign in attempts from your address. Try again in a minute.",
+	}
+)
+
+func (l *limiter) check(ip string, now time.Time) *rejection {
+	if l.limits.sessionsPerIP > 0 && l.open[ip] >= l.limits.sessionsPerIP {
+		return &tooManySessions
+	}
+	if l.newSessions.full(ip, l.limits.newSessionsPerIP, now) {
+		return &tooManyNewSessions
+	}
+	return nil
+}
+
+// acquire reserves a session for the client. The returned release must be
+// called when the session ends, unless a rejection is returned. Nothing is
+// counted for a rejected session.
+func (l *limiter) acquire(ip string, fingerprint string, now time.Time) (func(), *rejection) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if rejected := l.check(ip, now); rejected != nil {
+		return nil, rejected
+	}
+	if l.tokens.full(fingerprint, l.limits.tokensPerFingerprint, now) {
+		return nil, &tooManyTokens
+	}
+
+	l.newSessions.add(ip, l.limits.newSessionsPerIP, now)
+	l.tokens.add(fingerprint, l.limits.tokensPerFingerprint, now)
+	l.open[ip]++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.open[ip]--
+		if l.open[ip] <= 0 {
+			delete(l.open, ip)
+		}
+	}, nil
+}
+
+// signIn counts a call to the auth server during keyboard-interactive sign
+// in, before there's a session for limitMiddleware to see.
+func (l *limiter) signIn(ip string, now time.Time) *rejection {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.signIns.full(ip, l.limits.signInsPerIP, now) {
+		return &tooManySignIns
+	}
+	l.signIns.add(ip, l.limits.signInsPerIP, now)
+	return nil
+}
+
+// window counts events per key over a sliding period.
+type window struct {
+	period time.Duration
+	events map[string][]time.Time
+	swept  time.Time
+}
+
+func newWindow(period time.Duration) *window {
+	return &window{period: period, events: map[string][]time.Time{}}
+}
+
+// full reports whether the key already has limit events in the period. A
+// zero limit is never full.
+func (w *window) full(key string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return false
+	}
+	w.sweep(now)
+	return len(w.recent(key, now)) >= limit
+}
+
+// add records an event for the key. A zero limit records nothing.
+func (w *window) add(key string, limit int, now time.Time) {
+	if limit <= 0 {
+		return
+	}
+	w.events[key] = append(w.recent(key, now), now)
+}
+
+func (w *window) recent(key string, now time.Time) []time.Time {
+	events := w.events[key]
+	i := 0
+	for i < len(events) && now.Sub(events[i]) >= w.period {
+		i++
+	}
+	return events[i:]
+}
+
+// sweep forgets keys without recent events, at most once a period.
+func (w *window) sweep(now time.Time) {
+	if now.Sub(w.swept) < w.period {
+		return
+	}
+	w.swept = now
+	for key := range w.events {
+		if len(w.recent(key, now)) == 0 {
+			delete(w.events, key)
+		}
+	}
+}
+
+// limitMiddleware turns away clients over their limits before they reach
+// the shop. It runs once the client has a session rather than on the raw
+// connection, so the client is told why.
+func limitMiddleware(l *limiter) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			fingerprint := identity(s.Context()).Fingerprint
+			release, rejected := l.acquire(remoteIP(s.RemoteAddr()), fingerprint, time.Now())
+			if rejected != nil {
+				sessionLogger(s).Warn("session rejected", "reason", rejected.reason)
+				metrics.Rejections.Inc(rejected.reason)
+				wish.Fatalln(s, rejected.message)
+				return
+			}
+			defer release()
+			next(s)
+		}
+	}
+}
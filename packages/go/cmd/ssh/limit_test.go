@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(limits{sessionsPerIP: 2, newSessionsPerIP: 3, tokensPerFingerprint: 2})
+	now := time.Now()
+
+	first, rejected := l.acquire("10.0.0.1", "a", now)
+	if rejected != nil {
+		t.Fatalf("first session rejected: %s", rejected.reason)
+	}
+	if _, rejected = l.acquire("10.0.0.1", "b", now); rejected != nil {
+		t.Fatalf("second session rejected: %s", rejected.reason)
+	}
+	if _, rejected = l.acquire("10.0.0.1", "c", now); rejected != &tooManySessions {
+		t.Fatalf("expected %s, got %v", tooManySessions.reason, rejected)
+	}
+
+	first()
+	if _, rejected = l.acquire("10.0.0.1", "c", now); rejected != nil {
+		t.Fatalf("session after release rejected: %s", rejected.reason)
+	}
+	if _, rejected = l.acquire("10.0.0.2", "a", now); rejected != nil {
+		t.Fatalf("other address rejected: %s", rejected.reason)
+	}
+	if _, rejected = l.acquire("10.0.0.3", "a", now); rejected != &tooManyTokens {
+		t.Fatalf("expected %s, got %v", tooManyTokens.reason, rejected)
+	}
+	if _, rejected = l.acquire("10.0.0.3", "a", now.Add(time.Minute)); rejected != nil {
+		t.Fatalf("fingerprint still limited after a minute: %s", rejected.reason)
+	}
+}
+
+func TestLimiterNewSessions(t *testing.T) {
+	l := newLimiter(limits{newSessionsPerIP: 2})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		release, rejected := l.acquire("10.0.0.1", "", now)
+		if rejected != nil {
+			t.Fatalf("session %d rejected: %s", i, rejected.reason)
+		}
+		release()
+	}
+	if _, rejected := l.acquire("10.0.0.1", "", now.Add(30*time.Second)); rejected != &tooManyNewSessions {
+		t.Fatalf("expected %s, got %v", tooManyNewSessions.reason, rejected)
+	}
+	if _, rejected := l.acquire("10.0.0.1", "", now.Add(time.Minute)); rejected != nil {
+		t.Fatalf("address still limited after a minute: %s", rejected.reason)
+	}
+}
+
+func TestLimiterRejectedNotCounted(t *testing.T) {
+	l := newLimiter(limits{newSessionsPerIP: 2, tokensPerFingerprint: 1})
+	now := time.Now()
+
+	if _, rejected := l.acquire("10.0.0.1", "a", now); rejected != nil {
+		t.Fatalf("first session rejected: %s", rejected.reason)
+	}
+	for i := 0; i < 3; i++ {
+		if _, rejected := l.acquire("10.0.0.1", "a", now); rejected != &tooManyTokens {
+			t.Fatalf("expected %s, got %v", tooManyTokens.reason, rejected)
+		}
+	}
+	if _, rejected := l.acquire("10.0.0.1", "b", now); rejected != nil {
+		t.Fatalf("rejected sessions counted against the address: %s", rejected.reason)
+	}
+	if _, rejected := l.acquire("10.0.0.1", "c", now); rejected != &tooManyNewSessions {
+		t.Fatalf("expected %s, got %v", tooManyNewSessions.reason, rejected)
+	}
+}
+
+func TestLimiterSignIns(t *testing.T) {
+	l := newLimiter(limits{signInsPerIP: 2})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if rejected := l.signIn("10.0.0.1", now); rejected != nil {
+			t.Fatalf("sign in %d rejected: %s", i, rejected.reason)
+		}
+	}
+	if rejected := l.signIn("10.0.0.1", now); rejected != &tooManySignIns {
+		t.Fatalf("expected %s, got %v", tooManySignIns.reason, rejected)
+	}
+	if rejected := l.signIn("10.0.0.2", now); rejected != nil {
+		t.Fatalf("other address rejected: %s", rejected.reason)
+	}
+	if rejected := l.signIn("10.0.0.1", now.Add(time.Minute)); rejected != nil {
+		t.Fatalf("address still limited after a minute: %s", rejected.reason)
+	}
+}
+
+func TestLimitMiddlewareMessage(t *testing.T) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := gossh.MarshalPrivateKey(hostKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{}, 1)
+	s, err := wish.NewServer(
+		wish.WithHostKeyPEM(pem.EncodeToMemory(block)),
+		wish.WithMiddleware(
+			func(ssh.Handler) ssh.Handler {
+				return func(s ssh.Session) {
+					started <- struct{}{}
+					<-s.Context().Done()
+				}
+			},
+			limitMiddleware(newLimiter(limits{sessionsPerIP: 1})),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(listener)
+	defer s.Close()
+
+	client, err := gossh.Dial("tcp", listener.Addr().String(), &gossh.ClientConfig{
+		User:            "test",
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	first, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	if err := first.Shell(); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	second, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	second.Stderr = &stderr
+	if err := second.Run(""); err == nil {
+		t.Fatal("expected the second session to be rejected")
+	}
+	if got := stderr.String(); !strings.Contains(got, tooManySessions.message) {
+		t.Fatalf("expected %q, got %q", tooManySessions.message, got)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/terminaldotshop/terminal/go/pkg/metrics"
+)
+
+// sessionLogger returns a logger that tags every line with the session, so
+// lines from concurrent shoppers can be told apart.
+func sessionLogger(s ssh.Session) *slog.Logger {
+	return slog.New(log.Default()).With(
+		"session", s.Context().SessionID(),
+		"fingerprint", identity(s.Context()).Fingerprint,
+		"remote", s.RemoteAddr().String(),
+	)
+}
+
+// loggingMiddleware logs the start and end of every session and keeps
+// metrics.Sessions up to date.
+func loggingMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			logger := sessionLogger(s)
+			pty, _, active := s.Pty()
+			start := time.Now()
+			metrics.Sessions.Inc()
+			defer metrics.Sessions.Dec()
+			logger.Info(
+				"session started",
+				"command", s.Command(),
+				"pty", active,
+				"term", pty.Term,
+				"width", pty.Window.Width,
+				"height", pty.Window.Height,
+				"client", s.Context().ClientVersion(),
+			)
+			next(s)
+			logger.Info("session ended", "duration", time.Since(start))
+		}
+	}
+}
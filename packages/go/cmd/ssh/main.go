@@ -4,20 +4,20 @@ package main
 // and continually print up to date terminal information.
 
 import (
-	"crypto/md5"
 	_ "embed"
-	"encoding/hex"
 
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/terminaldotshop/terminal/go/pkg/metrics"
 	"github.com/terminaldotshop/terminal/go/pkg/resource"
 	"github.com/terminaldotshop/terminal/go/pkg/tui"
 
@@ -27,20 +27,26 @@ import (
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
-	"github.com/charmbracelet/wish/logging"
-	gossh "golang.org/x/crypto/ssh"
+	"github.com/muesli/termenv"
 )
 
-type PasswordState int
-
-const (
-	PasswordSkip PasswordState = iota
-	PasswordPossible
-	PasswordWaiting
-	PasswordAccepted
-)
+// required is the configuration read unconditionally while starting up and
+// signing sessions in.
+var required = []string{
+	"AuthFingerprintKey.value",
+	"SSHKey.private",
+	"StripePublic.value",
+}
 
 func main() {
+	err := resource.Load(resource.Options{
+		File:     os.Getenv("TERMINAL_CONFIG"),
+		Required: required,
+	})
+	if err != nil {
+		log.Fatal("Could not load configuration", "error", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -56,27 +62,40 @@ func main() {
 	if httpPort == "" {
 		httpPort = "8000"
 	}
+	// ECS kills the task 30 seconds after SIGTERM by default
+	drainTimeout := 25 * time.Second
+	if value := os.Getenv("DRAIN_TIMEOUT"); value != "" {
+		drainTimeout, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("Invalid DRAIN_TIMEOUT", "error", err)
+		}
+	}
+	idleTimeout := 15 * time.Minute
+	if value := os.Getenv("IDLE_TIMEOUT"); value != "" {
+		idleTimeout, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("Invalid IDLE_TIMEOUT", "error", err)
+		}
+	}
+
+	shops := newPrograms(idleTimeout)
+	limits := newLimiter(loadLimits())
 
 	s, err := wish.NewServer(
 		wish.WithAddress(net.JoinHostPort("0.0.0.0", sshPort)),
 		wish.WithHostKeyPEM([]byte(resource.Resource.SSHKey.Private)),
 		wish.WithMiddleware(
-			bubbletea.Middleware(teaHandler),
+			bubbletea.MiddlewareWithProgramHandler(shops.handler, termenv.Ascii),
 			activeterm.Middleware(), // Bubble Tea apps usually require a PTY.
-			logging.Middleware(),
+			commandMiddleware(),     // ssh terminal.shop <command> doesn't.
+			loggingMiddleware(),
+			limitMiddleware(limits),
 		),
 		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
-			hash := md5.Sum(key.Marshal())
-			fingerprint := hex.EncodeToString(hash[:])
-			ctx.SetValue("fingerprint", fingerprint)
+			ctx.SetValue("identity", keyIdentity(key))
 			return true
 		}),
-		wish.WithKeyboardInteractiveAuth(
-			func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
-				ctx.SetValue("fingerprint", uuid.NewString())
-				return true
-			},
-		),
+		wish.WithKeyboardInteractiveAuth(keyboardInteractiveAuth(limits)),
 	)
 	if err != nil {
 		log.Error("Could not start server", "error", err)
@@ -93,6 +112,15 @@ func main() {
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "https://www.terminal.shop", http.StatusFound)
 	})
+	http.Handle("/metrics", metrics.Default)
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		// fail while draining so the load balancer stops sending shoppers
+		if shops.isDraining() {
+			http.Error(w, "draining", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
 
 	// Listen on port 80
 	go func() {
@@ -104,7 +132,15 @@ func main() {
 	}()
 
 	<-ctx.Done()
-	s.Shutdown(ctx)
+	// Stop accepting connections and give checkouts in progress a chance to
+	// finish, the other sessions close after showing a banner.
+	log.Info("Draining sessions", "sessions", shops.drain(), "timeout", drainTimeout)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancelDrain()
+	if err := s.Shutdown(drainCtx); err != nil {
+		log.Warn("Closing sessions still open after the drain timeout", "error", err)
+		s.Close()
+	}
 	slog.Info("Shutting down server")
 }
 
@@ -129,16 +165,19 @@ func (s *sshOutput) Fd() uintptr {
 // handles the incoming ssh.Session. Here we just grab the terminal info and
 // pass it to the new model. You can also return tea.ProgramOptions (such as
 // tea.WithAltScreen) on a session by session basis.
-func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+func teaHandler(s ssh.Session, idleTimeout time.Duration) (tea.Model, []tea.ProgramOption) {
 	pty, _, _ := s.Pty()
 	sessionBridge := &sshOutput{
 		Session: s,
 		tty:     pty.Slave,
 	}
 	renderer := bubbletea.MakeRenderer(sessionBridge)
-	fingerprint := s.Context().Value("fingerprint").(string)
-	slog.Info("got fingerprint", "fingerprint", fingerprint)
-	model, err := tui.NewModel(renderer, fingerprint)
+	session := metrics.NewSession()
+	go func() {
+		<-s.Context().Done()
+		session.Close()
+	}()
+	model, err := tui.NewModel(renderer, identity(s.Context()), sessionLogger(s), session, idleTimeout)
 	if err != nil {
 		return nil, []tea.ProgramOption{}
 	}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/terminaldotshop/terminal/go/pkg/resource"
+)
+
+func TestRequiredConfig(t *testing.T) {
+	for _, name := range []string{"Api", "Auth", "StripePublic", "AuthFingerprintKey", "SSHKey"} {
+		t.Setenv("SST_RESOURCE_"+name, "{}")
+	}
+
+	err := resource.Load(resource.Options{Required: required})
+
+	var validation *resource.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if !reflect.DeepEqual(validation.Missing, required) {
+		t.Errorf("expected %v to be missing, got %v", required, validation.Missing)
+	}
+}
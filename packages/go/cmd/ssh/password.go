@@ -0,0 +1,226 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/metrics"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// PasswordState tracks a keyboard-interactive sign in, for people connecting
+// without an SSH key.
+type PasswordState int
+
+const (
+	// PasswordSkip continues as a new account with a fresh claim code.
+	PasswordSkip PasswordState = iota
+	// PasswordPossible is asking for an email or a claim code.
+	PasswordPossible
+	// PasswordWaiting is waiting for the code sent to an email.
+	PasswordWaiting
+	// PasswordAccepted signed in with an email code or the claim code of an
+	// existing account.
+	PasswordAccepted
+)
+
+// passwordAttempts is how many wrong answers a connection gets before it's
+// disconnected.
+const passwordAttempts = 3
+
+const passwordInstruction = `Sign in to terminal.shop without an SSH key.
+Enter your email to get a one-time code, the claim code of an account you
+used before, or nothing to start a new one.`
+
+// keyboardInteractiveAuth signs in with an email code or a claim code. The
+// auth server is only asked while the limiter allows the client's address.
+func keyboardInteractiveAuth(l *limiter) ssh.KeyboardInteractiveHandler {
+	return func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+		return passwordSignIn(ctx, challenger, l)
+	}
+}
+
+func passwordSignIn(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge, l *limiter) bool {
+	logger := log.With("session", ctx.SessionID(), "remote", ctx.RemoteAddr().String())
+
+	state := PasswordPossible
+	identity := api.Identity{}
+	email := ""
+	notice := ""
+	for failed := 0; failed < passwordAttempts; {
+		switch state {
+		case PasswordPossible:
+			answer, ok := ask(challenger, notice+passwordInstruction, "email or claim code: ")
+			if !ok {
+				return false
+			}
+			notice = ""
+
+			switch {
+			case answer == "":
+				code, err := newClaimCode()
+				if err != nil {
+					logger.Error("could not create a claim code", "error", err)
+					return false
+				}
+				identity = claimIdentity(code)
+				state = PasswordSkip
+			case strings.Contains(answer, "@"):
+				if signInLimited(ctx, challenger, l) {
+					return false
+				}
+				if err := api.SendEmailCode(answer, remoteIP(ctx.RemoteAddr())); err != nil {
+					logger.Error("could not send email code", "error", err)
+					notice = "Could not send a code to that email.\n\n"
+					failed++
+					continue
+				}
+				email = answer
+				state = PasswordWaiting
+			default:
+				code, ok := parseClaimCode(answer)
+				if !ok {
+					notice = "That isn't a claim code.\n\n"
+					failed++
+					continue
+				}
+				if signInLimited(ctx, challenger, l) {
+					return false
+				}
+				identity = claimIdentity(code)
+				credentials, err := api.FetchClaimToken(identity)
+				if errors.Is(err, api.ErrNoAccount) {
+					metrics.AuthFailures.Inc("claim_code")
+					notice = "No account with that claim code.\n\n"
+					failed++
+					continue
+				}
+				if err != nil {
+					logger.Error("could not sign in with claim code", "error", err)
+					notice = "Could not sign in, try again.\n\n"
+					failed++
+					continue
+				}
+				identity.Credentials = credentials
+				state = PasswordAccepted
+			}
+		case PasswordWaiting:
+			answer, ok := ask(challenger, notice+"We sent a code to "+email+".", "code: ")
+			if !ok {
+				return false
+			}
+
+			if signInLimited(ctx, challenger, l) {
+				return false
+			}
+			credentials, err := api.FetchEmailToken(email, answer)
+			if err != nil {
+				metrics.AuthFailures.Inc("email_code")
+				notice = "That code didn't work.\n\n"
+				failed++
+				continue
+			}
+			identity = api.Identity{
+				Fingerprint: emailFingerprint(email),
+				Credentials: credentials,
+			}
+			state = PasswordAccepted
+		}
+
+		if state == PasswordSkip || state == PasswordAccepted {
+			ctx.SetValue("identity", identity)
+			return true
+		}
+	}
+
+	metrics.AuthFailures.Inc("keyboard_interactive")
+	logger.Warn("keyboard-interactive sign in failed", "state", state)
+	return false
+}
+
+// signInLimited counts a call to the auth server against the client's
+// address, and tells them when they're over the limit.
+func signInLimited(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge, l *limiter) bool {
+	rejected := l.signIn(remoteIP(ctx.RemoteAddr()), time.Now())
+	if rejected == nil {
+		return false
+	}
+	log.Warn("sign in rejected", "session", ctx.SessionID(), "remote", ctx.RemoteAddr().String(), "reason", rejected.reason)
+	metrics.Rejections.Inc(rejected.reason)
+	challenger("", rejected.message, nil, nil)
+	return true
+}
+
+func ask(challenger gossh.KeyboardInteractiveChallenge, instruction string, question string) (string, bool) {
+	answers, err := challenger("", instruction, []string{question}, []bool{true})
+	if err != nil || len(answers) != 1 {
+		return "", false
+	}
+	return strings.TrimSpace(answers[0]), true
+}
+
+const claimAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newClaimCode returns a code like 7KQ2-M9XD-4RTB.
+func newClaimCode() (string, error) {
+	bytes := make([]byte, 12)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	for i, b := range bytes {
+		bytes[i] = claimAlphabet[b&31]
+	}
+	return formatClaimCode(string(bytes)), nil
+}
+
+// parseClaimCode normalizes a claim code typed by hand.
+func parseClaimCode(input string) (string, bool) {
+	code := strings.NewReplacer("-", "", " ", "").Replace(strings.ToUpper(input))
+	if len(code) != 12 {
+		return "", false
+	}
+	for _, char := range code {
+		if !strings.ContainsRune(claimAlphabet, char) {
+			return "", false
+		}
+	}
+	return formatClaimCode(code), true
+}
+
+func formatClaimCode(code string) string {
+	return code[:4] + "-" + code[4:8] + "-" + code[8:]
+}
+
+// claimIdentity signs in with a claim code. Only its hash is sent, so the
+// code can't be recovered from the account.
+func claimIdentity(code string) api.Identity {
+	hash := sha256.Sum256([]byte(code))
+	return api.Identity{
+		Fingerprint: "claim:" + hex.EncodeToString(hash[:]),
+		Claim:       code,
+	}
+}
+
+// remoteIP is the host of addr, without the port.
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// emailFingerprint identifies an email session in logs and limits without
+// writing the address down.
+func emailFingerprint(email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(email)))
+	return "email:" + hex.EncodeToString(hash[:8])
+}
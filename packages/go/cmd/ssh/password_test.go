@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/fakeapi"
+	"github.com/terminaldotshop/terminal/go/pkg/resource"
+)
+
+func TestClaimCode(t *testing.T) {
+	code, err := newClaimCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != 14 {
+		t.Fatalf("unexpected claim code %q", code)
+	}
+
+	// typed by hand, lowercase and without dashes
+	typed := ""
+	for _, char := range code {
+		if char != '-' {
+			typed += string(char | 0x20)
+		}
+	}
+	parsed, ok := parseClaimCode(typed)
+	if !ok || parsed != code {
+		t.Errorf("parsed %q as %q, want %q", typed, parsed, code)
+	}
+	if claimIdentity(parsed) != claimIdentity(code) {
+		t.Error("expected the same identity for the same claim code")
+	}
+
+	for _, input := range []string{"", "ABCD-EFGH", "ABCD-EFGH-IJKU", "ABCD-EFGH-JKMNP"} {
+		if _, ok := parseClaimCode(input); ok {
+			t.Errorf("expected %q to be rejected", input)
+		}
+	}
+}
+
+// signInContext is just enough of a connection for passwordSignIn.
+type signInContext struct {
+	ssh.Context
+	identity interface{}
+}
+
+func (c *signInContext) SessionID() string { return "test" }
+
+func (c *signInContext) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 2222}
+}
+
+func (c *signInContext) SetValue(key, value interface{}) { c.identity = value }
+
+func TestPasswordSignInClaimCode(t *testing.T) {
+	fake := fakeapi.New(fakeapi.DefaultSeed())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	authUrl := resource.Resource.Auth.Url
+	defer func() { resource.Resource.Auth.Url = authUrl }()
+	resource.Resource.Auth.Url = server.URL + "/auth"
+
+	existing, err := newClaimCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.SignIn(claimIdentity(existing).Fingerprint)
+	mistyped, err := newClaimCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []string{mistyped, existing}
+	instructions := []string{}
+	challenger := func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+		instructions = append(instructions, instruction)
+		if len(answers) == 0 {
+			return nil, io.EOF
+		}
+		answer := answers[0]
+		answers = answers[1:]
+		return []string{answer}, nil
+	}
+
+	ctx := &signInContext{}
+	if !passwordSignIn(ctx, challenger, newLimiter(limits{})) {
+		t.Fatal("expected the existing claim code to sign in")
+	}
+	if !strings.HasPrefix(instructions[1], "No account with that claim code.") {
+		t.Errorf("expected the mistyped code to be rejected, got %q", instructions[1])
+	}
+	identity := ctx.identity.(api.Identity)
+	if identity.Claim != existing || identity.Credentials == nil {
+		t.Errorf("expected to sign in as %s, got %+v", existing, identity)
+	}
+}
@@ -3,6 +3,7 @@ module github.com/terminaldotshop/terminal/go
 go 1.22.1
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/charmbracelet/bubbles v0.18.0
 	github.com/charmbracelet/bubbletea v0.26.1
 	github.com/charmbracelet/huh v0.3.0
@@ -10,10 +11,13 @@ require (
 	github.com/charmbracelet/log v0.4.0
 	github.com/charmbracelet/ssh v0.0.0-20240401141849-854cddfa2917
 	github.com/charmbracelet/wish v1.4.0
+	github.com/foize/go.sgr v0.0.0-20140220094842-40bdfc98040c
 	github.com/google/uuid v1.6.0
+	github.com/muesli/termenv v0.15.2
 	github.com/stripe/stripe-go/v78 v78.2.0
 	github.com/terminaldotshop/terminal-sdk-go v0.1.0-alpha.46
 	golang.org/x/crypto v0.21.0
+	rsc.io/qr v0.2.0
 )
 
 require (
@@ -28,7 +32,6 @@ require (
 	github.com/creack/pty v1.1.21 // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f // indirect
-	github.com/foize/go.sgr v0.0.0-20140220094842-40bdfc98040c // indirect
 	github.com/go-logfmt/logfmt v0.6.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -37,7 +40,6 @@ require (
 	github.com/muesli/ansi v0.0.0-20230316100256-276c6243b2f6 // indirect
 	github.com/muesli/cancelreader v0.2.2 // indirect
 	github.com/muesli/reflow v0.3.0 // indirect
-	github.com/muesli/termenv v0.15.2 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/tidwall/gjson v1.17.1 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
@@ -48,7 +50,6 @@ require (
 	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/term v0.20.0 // indirect
 	golang.org/x/text v0.15.0 // indirect
-	rsc.io/qr v0.2.0 // indirect
 )
 
 replace github.com/charmbracelet/huh => github.com/adamdottv/huh v0.3.2-0.20240510151548-bbaef6474d1d
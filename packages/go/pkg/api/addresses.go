@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Defaults are the address and card checkout starts with. Either is empty
+// when it isn't set.
+type Defaults struct {
+	AddressID string `json:"defaultAddressID"`
+	CardID    string `json:"defaultCardID"`
+}
+
+func GetDefaults(ctx context.Context, client *terminal.Client) (Defaults, error) {
+	res := struct {
+		Data struct {
+			User Defaults `json:"user"`
+		} `json:"data"`
+	}{}
+	err := client.Get(ctx, "profile", nil, &res)
+	return res.Data.User, err
+}
+
+func SetDefaultAddress(ctx context.Context, client *terminal.Client, id string) error {
+	params := map[string]string{"defaultAddressID": id}
+	return client.Put(ctx, "profile", params, nil)
+}
+
+func SetDefaultCard(ctx context.Context, client *terminal.Client, id string) error {
+	params := map[string]string{"defaultCardID": id}
+	return client.Put(ctx, "profile", params, nil)
+}
+
+// UpdateAddress replaces the address in place, it keeps its ID.
+func UpdateAddress(ctx context.Context, client *terminal.Client, id string, params terminal.AddressNewParams) error {
+	return client.Put(ctx, "address/"+url.PathEscape(id), params, nil)
+}
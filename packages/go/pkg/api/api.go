@@ -1,3 +1,10 @@
+// Package api talks to the Terminal API and the auth server for the shop.
+//
+// Some endpoints aren't in the SDK yet: personal access tokens, SSH keys,
+// address edits and defaults, promo codes, shipping methods, subscription
+// changes and parts of the cart and orders. Those are called through the
+// SDK's client directly, with its generic Get, Post, Put and Delete, and
+// decoded into the types in this package until the SDK catches up.
 package api
 
 import (
@@ -5,12 +12,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/stripe/stripe-go/v78"
 	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/metrics"
 	"github.com/terminaldotshop/terminal/go/pkg/resource"
 
 	"github.com/stripe/stripe-go/v78/token"
@@ -20,6 +31,25 @@ func Init() {
 	stripe.Key = resource.Resource.StripePublic.Value
 }
 
+// Identity is who an SSH session signs in as.
+type Identity struct {
+	// Fingerprint is the SHA256 fingerprint of the key, as printed by
+	// ssh-keygen -l. Keyboard-interactive sessions derive it from their
+	// claim code or email instead.
+	Fingerprint string
+	// Legacy is the MD5 fingerprint accounts were keyed by before SHA256.
+	// The auth endpoint links it to Fingerprint the first time it's seen.
+	Legacy string
+	// Key describes the key like ssh-keygen -l, empty without one.
+	Key string
+	// Claim is the code that signs back in to a keyboard-interactive
+	// account, empty for everyone else.
+	Claim string
+	// Credentials are set when the session signed in with an email code
+	// during authentication, instead of by fingerprint.
+	Credentials *UserCredentials
+}
+
 type FingerprintRequest struct {
 	Fingerprint string `json:"fingerprint"`
 }
@@ -37,21 +67,94 @@ func GetErrorMessage(err error) string {
 	}
 }
 
-func FetchUserToken(publicKey string) (*UserCredentials, error) {
+// GetOrderCreated decodes the creation time from the ULID embedded in an
+// order ID (ord_XXXXXXXXXXXXXXXXXXXXXXXXXX).
+func GetOrderCreated(orderID string) (time.Time, bool) {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	id := orderID[strings.LastIndex(orderID, "_")+1:]
+	if len(id) < 10 {
+		return time.Time{}, false
+	}
+
+	var ms int64
+	for _, char := range strings.ToUpper(id[:10]) {
+		value := strings.IndexRune(alphabet, char)
+		if value == -1 {
+			return time.Time{}, false
+		}
+		ms = ms<<5 | int64(value)
+	}
+
+	return time.UnixMilli(ms).UTC(), true
+}
+
+func FetchUserToken(identity Identity) (*UserCredentials, error) {
+	data := url.Values{}
+	data.Set("fingerprint", identity.Fingerprint)
+	if identity.Legacy != "" {
+		data.Set("legacy_fingerprint", identity.Legacy)
+	}
+	return fetchToken(data)
+}
+
+// ErrNoAccount is returned by FetchClaimToken when no account has the
+// identity's fingerprint.
+var ErrNoAccount = errors.New("no account for that fingerprint")
+
+// FetchClaimToken signs in the account a claim code was handed out for.
+// Unlike FetchUserToken it doesn't create an account that isn't there, so a
+// mistyped code isn't mistaken for a new one.
+func FetchClaimToken(identity Identity) (*UserCredentials, error) {
+	data := url.Values{}
+	data.Set("fingerprint", identity.Fingerprint)
+	data.Set("existing", "true")
+	return fetchToken(data)
+}
+
+// SendEmailCode emails a one-time code for FetchEmailToken.
+func SendEmailCode(email string, remoteIP string) error {
+	data := url.Values{}
+	data.Set("client_secret", resource.Resource.AuthFingerprintKey.Value)
+	data.Set("email", email)
+	// the auth server limits codes per address of the session asking
+	data.Set("remote_ip", remoteIP)
+	resp, err := http.PostForm(resource.Resource.Auth.Url+"/ssh/code", data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.New("failed to send code: " + string(body))
+	}
+	return nil
+}
+
+// FetchEmailToken signs in the account for email with a code from
+// SendEmailCode, creating the account if there isn't one.
+func FetchEmailToken(email string, code string) (*UserCredentials, error) {
 	data := url.Values{}
+	data.Set("email", email)
+	data.Set("code", code)
+	return fetchToken(data)
+}
+
+func fetchToken(data url.Values) (*UserCredentials, error) {
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", "ssh")
 	data.Set("client_secret", resource.Resource.AuthFingerprintKey.Value)
-	data.Set("fingerprint", publicKey)
 	data.Set("provider", "ssh")
 	resp, err := http.PostForm(resource.Resource.Auth.Url+"/token", data)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNoAccount
+	}
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		fmt.Println(string(body))
 		return nil, errors.New(fmt.Sprintf("failed to auth: " + string(body)))
 	}
 	credentials := UserCredentials{}
@@ -62,6 +165,63 @@ func FetchUserToken(publicKey string) (*UserCredentials, error) {
 	return &credentials, nil
 }
 
+// NewUserClient signs in the user identified by identity and returns an API
+// client authorized as that user. Every call the client makes is logged to
+// logger.
+func NewUserClient(identity Identity, logger *slog.Logger) (*terminal.Client, *UserCredentials, error) {
+	// already signed in with an email code
+	token := identity.Credentials
+	if token == nil {
+		start := time.Now()
+		var err error
+		token, err = FetchUserToken(identity)
+		if err != nil {
+			logger.Error("sign in failed", "duration", time.Since(start), "error", err)
+			metrics.AuthFailures.Inc("sign_in")
+			return nil, nil, err
+		}
+		logger.Info("signed in", "duration", time.Since(start))
+	}
+
+	client := terminal.NewClient(
+		option.WithBaseURL(resource.Resource.Api.Url),
+		option.WithBearerToken(token.AccessToken),
+		option.WithMiddleware(LogRequests(logger)),
+	)
+	return client, token, nil
+}
+
+// LogRequests is a client middleware that logs the method, path, status and
+// latency of every API call, and records them in metrics.APIDuration.
+func LogRequests(logger *slog.Logger) option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		start := time.Now()
+		res, err := next(req)
+		duration := time.Since(start)
+		attrs := []any{
+			"method", req.Method,
+			"path", req.URL.Path,
+			"duration", duration,
+		}
+
+		status := 0
+		if err == nil {
+			status = res.StatusCode
+		}
+		metrics.ObserveAPI(req.Method, req.URL.Path, status, duration)
+
+		switch {
+		case err != nil:
+			logger.Error("api call failed", append(attrs, "error", err)...)
+		case res.StatusCode >= 400:
+			logger.Warn("api call failed", append(attrs, "status", res.StatusCode)...)
+		default:
+			logger.Info("api call", append(attrs, "status", res.StatusCode)...)
+		}
+		return res, err
+	}
+}
+
 func StripeCreditCard(card *stripe.CardParams) (*stripe.Token, *string) {
 	tokenParams := &stripe.TokenParams{Card: card}
 	tokenResult, err := token.New(tokenParams)
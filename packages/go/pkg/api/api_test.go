@@ -10,6 +10,9 @@ import (
 )
 
 func TestGetProduct(t *testing.T) {
+	if err := resource.Load(resource.Options{}); err != nil {
+		t.Fatal(err)
+	}
 	client := terminal.NewClient(
 		option.WithBaseURL(resource.Resource.Api.Url),
 	)
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// CartExtras are the parts of the cart the SDK's Cart doesn't have yet, read
+// with one request.
+type CartExtras struct {
+	// Promo is the applied promo code, nil when there isn't one.
+	Promo *Promo
+	// Discount is what Promo takes off the cart, in cents.
+	Discount     int64
+	FreeShipping FreeShipping
+}
+
+func GetCartExtras(ctx context.Context, client *terminal.Client) (CartExtras, error) {
+	res := struct {
+		Data struct {
+			Promo  *Promo `json:"promo"`
+			Amount struct {
+				Discount int64 `json:"discount"`
+			} `json:"amount"`
+			FreeShipping FreeShipping `json:"freeShipping"`
+		} `json:"data"`
+	}{}
+	err := client.Get(ctx, "cart", nil, &res)
+	return CartExtras{
+		Promo:        res.Data.Promo,
+		Discount:     res.Data.Amount.Discount,
+		FreeShipping: res.Data.FreeShipping,
+	}, err
+}
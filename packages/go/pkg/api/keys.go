@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Key is an SSH key linked to an account.
+type Key struct {
+	ID          string  `json:"id"`
+	Fingerprint string  `json:"fingerprint"`
+	Time        KeyTime `json:"time"`
+}
+
+type KeyTime struct {
+	Created string `json:"created"`
+}
+
+// KeyPairing is a short-lived code that links another key to the account.
+type KeyPairing struct {
+	Code    string `json:"code"`
+	Expires string `json:"expires"`
+}
+
+func ListKeys(ctx context.Context, client *terminal.Client) ([]Key, error) {
+	res := struct {
+		Data []Key `json:"data"`
+	}{}
+	err := client.Get(ctx, "key", nil, &res)
+	return res.Data, err
+}
+
+func PairKey(ctx context.Context, client *terminal.Client) (KeyPairing, error) {
+	res := struct {
+		Data KeyPairing `json:"data"`
+	}{}
+	err := client.Post(ctx, "key/pair", nil, &res)
+	return res.Data, err
+}
+
+func DeleteKey(ctx context.Context, client *terminal.Client, id string) error {
+	return client.Delete(ctx, "key/"+url.PathEscape(id), nil, nil)
+}
+
+// FetchPairedToken signs in with a pairing code from another session, which
+// links the identity's key to that session's account.
+func FetchPairedToken(identity Identity, code string) (*UserCredentials, error) {
+	data := url.Values{}
+	data.Set("fingerprint", identity.Fingerprint)
+	if identity.Legacy != "" {
+		data.Set("legacy_fingerprint", identity.Legacy)
+	}
+	data.Set("pairing_code", code)
+	return fetchToken(data)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+const (
+	PromoKindPercent = "percent"
+	PromoKindAmount  = "amount"
+)
+
+// Promo is a promo or gift code applied to the cart.
+type Promo struct {
+	Code string `json:"code"`
+	// Kind is PromoKindPercent or PromoKindAmount.
+	Kind string `json:"kind"`
+	// Value is the percentage off for percent promos, or the balance left in
+	// cents for amount promos, which orders spend down like a gift card.
+	Value int64 `json:"value"`
+}
+
+// Discount is how much the promo takes off subtotal, in cents. It never takes
+// off more than subtotal.
+func (p Promo) Discount(subtotal int64) int64 {
+	if p.Kind == PromoKindPercent {
+		return subtotal * min(p.Value, 100) / 100
+	}
+	return min(p.Value, subtotal)
+}
+
+type orderDiscount struct {
+	ID     string `json:"id"`
+	Amount struct {
+		Discount int64 `json:"discount"`
+	} `json:"amount"`
+}
+
+// GetOrderDiscount returns what a promo took off the order, in cents.
+func GetOrderDiscount(ctx context.Context, client *terminal.Client, id string) (int64, error) {
+	res := struct {
+		Data orderDiscount `json:"data"`
+	}{}
+	err := client.Get(ctx, "order/"+id, nil, &res)
+	return res.Data.Amount.Discount, err
+}
+
+// OrderDiscounts are what promos took off the user's orders, by order ID.
+// Orders without a promo aren't in it.
+type OrderDiscounts map[string]int64
+
+func GetOrderDiscounts(ctx context.Context, client *terminal.Client) (OrderDiscounts, error) {
+	res := struct {
+		Data []orderDiscount `json:"data"`
+	}{}
+	if err := client.Get(ctx, "order", nil, &res); err != nil {
+		return nil, err
+	}
+	discounts := OrderDiscounts{}
+	for _, order := range res.Data {
+		if order.Amount.Discount > 0 {
+			discounts[order.ID] = order.Amount.Discount
+		}
+	}
+	return discounts, nil
+}
+
+func SetCartPromo(ctx context.Context, client *terminal.Client, code string) (*Promo, error) {
+	res := struct {
+		Data Promo `json:"data"`
+	}{}
+	params := map[string]string{"code": code}
+	if err := client.Put(ctx, "cart/promo", params, &res); err != nil {
+		return nil, err
+	}
+	return &res.Data, nil
+}
+
+func RemoveCartPromo(ctx context.Context, client *terminal.Client) error {
+	return client.Delete(ctx, "cart/promo", nil, nil)
+}
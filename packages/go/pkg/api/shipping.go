@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// ShippingRate is a shipping method available for the cart.
+type ShippingRate struct {
+	ID      string `json:"id"`
+	Service string `json:"service"`
+	// Amount is what the method costs for the cart, in cents.
+	Amount    int64  `json:"amount"`
+	Timeframe string `json:"timeframe"`
+}
+
+// ListShippingRates returns the methods for the cart's address, cheapest
+// first.
+func ListShippingRates(ctx context.Context, client *terminal.Client) ([]ShippingRate, error) {
+	res := struct {
+		Data []ShippingRate `json:"data"`
+	}{}
+	err := client.Get(ctx, "cart/shipping", nil, &res)
+	return res.Data, err
+}
+
+func SetShippingRate(ctx context.Context, client *terminal.Client, id string) error {
+	params := map[string]string{"shippingRateID": id}
+	return client.Put(ctx, "cart/shipping", params, nil)
+}
+
+// FreeShipping is when the cart ships for free: a subtotal of at least
+// Threshold cents shipped to Country.
+type FreeShipping struct {
+	Threshold int64  `json:"threshold"`
+	Country   string `json:"country"`
+}
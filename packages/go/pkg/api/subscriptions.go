@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// SubscriptionFrequencyBiweekly ships every two weeks. The SDK's frequency
+// enum doesn't have it yet, but the API accepts it, see
+// packages/core/test/subscription.test.ts.
+const SubscriptionFrequencyBiweekly terminal.SubscriptionFrequency = "biweekly"
+
+// Subscription is a product shipped on a schedule.
+type Subscription struct {
+	ID               string                         `json:"id"`
+	AddressID        string                         `json:"addressID"`
+	CardID           string                         `json:"cardID"`
+	Frequency        terminal.SubscriptionFrequency `json:"frequency"`
+	ProductVariantID string                         `json:"productVariantID"`
+	Quantity         int64                          `json:"quantity"`
+	// Next is the next shipment in RFC 3339, empty before one is scheduled.
+	Next string `json:"next"`
+	// Paused subscriptions don't ship until they're resumed.
+	Paused bool `json:"paused"`
+}
+
+// SubscriptionParams are the fields of a subscription that can change. Empty
+// fields are left as they are.
+type SubscriptionParams struct {
+	AddressID        string `json:"addressID,omitempty"`
+	CardID           string `json:"cardID,omitempty"`
+	ProductVariantID string `json:"productVariantID,omitempty"`
+}
+
+func ListSubscriptions(ctx context.Context, client *terminal.Client) ([]Subscription, error) {
+	res := struct {
+		Data []Subscription `json:"data"`
+	}{}
+	err := client.Get(ctx, "subscription", nil, &res)
+	return res.Data, err
+}
+
+func UpdateSubscription(ctx context.Context, client *terminal.Client, id string, params SubscriptionParams) error {
+	return client.Put(ctx, "subscription/"+url.PathEscape(id), params, nil)
+}
+
+func PauseSubscription(ctx context.Context, client *terminal.Client, id string) error {
+	return client.Post(ctx, "subscription/"+url.PathEscape(id)+"/pause", nil, nil)
+}
+
+// ResumeSubscription restarts shipments. When the next shipment passed while
+// it was paused, the schedule starts over from today.
+func ResumeSubscription(ctx context.Context, client *terminal.Client, id string) error {
+	return client.Post(ctx, "subscription/"+url.PathEscape(id)+"/resume", nil, nil)
+}
+
+// SkipSubscription moves the next shipment one period later.
+func SkipSubscription(ctx context.Context, client *terminal.Client, id string) error {
+	return client.Post(ctx, "subscription/"+url.PathEscape(id)+"/skip", nil, nil)
+}
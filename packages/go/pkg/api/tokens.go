@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Token is a personal access token.
+type Token struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+	// Label is empty for tokens created without one.
+	Label string     `json:"label"`
+	Scope TokenScope `json:"scope"`
+	Time  TokenTime  `json:"time"`
+}
+
+// TokenTime has the token's timestamps in RFC 3339. Expires and Used are
+// empty when the token never expires or hasn't been used.
+type TokenTime struct {
+	Created string `json:"created"`
+	Expires string `json:"expires"`
+	Used    string `json:"used"`
+}
+
+type TokenScope string
+
+const (
+	// TokenScopeRead can view orders and products.
+	TokenScopeRead TokenScope = "read"
+	// TokenScopeCart can also manage the cart and place orders.
+	TokenScopeCart TokenScope = "cart"
+	// TokenScopeFull can do everything the account can.
+	TokenScopeFull TokenScope = "full"
+)
+
+// Expired reports whether the token stopped working before now.
+func (t Token) Expired(now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, t.Time.Expires)
+	return err == nil && !now.Before(expires)
+}
+
+// TokenParams are the options for a new token. The zero value is a token
+// with full access that never expires.
+type TokenParams struct {
+	Label   string     `json:"label,omitempty"`
+	Scope   TokenScope `json:"scope,omitempty"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+func ListTokens(ctx context.Context, client *terminal.Client) ([]Token, error) {
+	res := struct {
+		Data []Token `json:"data"`
+	}{}
+	err := client.Get(ctx, "token", nil, &res)
+	return res.Data, err
+}
+
+// NewToken creates a token. The response is the only time the full token is
+// available.
+func NewToken(ctx context.Context, client *terminal.Client, params TokenParams) (terminal.TokenNewResponseData, error) {
+	res := terminal.TokenNewResponse{}
+	err := client.Post(ctx, "token", params, &res)
+	return res.Data, err
+}
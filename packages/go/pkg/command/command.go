@@ -0,0 +1,518 @@
+// Package command implements the non-interactive shop commands shared by the
+// CLI and the SSH server's exec mode.
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/output"
+)
+
+type Context struct {
+	Client *terminal.Client
+	Out    io.Writer
+	// Err gets flag errors and usage, so they don't mix with the output
+	Err  io.Writer
+	JSON bool
+}
+
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	run         func(ctx context.Context, c Context, args []string) error
+}
+
+// aliases map the single word commands accepted over ssh to their full name.
+var aliases = map[string]string{
+	"products":      "products list",
+	"cart":          "cart show",
+	"addresses":     "address list",
+	"cards":         "card list",
+	"subscriptions": "subscriptions list",
+	"orders":        "orders list",
+}
+
+var Commands = []Command{
+	{
+		Name:        "products list",
+		Usage:       "products list",
+		Description: "list products and their variants",
+		run:         productsList,
+	},
+	{
+		Name:        "cart show",
+		Usage:       "cart show",
+		Description: "show the items in your cart",
+		run:         cartShow,
+	},
+	{
+		Name:        "cart add",
+		Usage:       "cart add <variant> <qty>",
+		Description: "add qty of a product variant to your cart",
+		run:         cartAdd,
+	},
+	{
+		Name:        "address list",
+		Usage:       "address list",
+		Description: "list your shipping addresses",
+		run:         addressList,
+	},
+	{
+		Name:        "card list",
+		Usage:       "card list",
+		Description: "list your payment methods",
+		run:         cardList,
+	},
+	{
+		Name:        "subscriptions list",
+		Usage:       "subscriptions list",
+		Description: "list your subscriptions",
+		run:         subscriptionsList,
+	},
+	{
+		Name:        "order place",
+		Usage:       "order place [-address <id>] [-card <id>]",
+		Description: "place an order for the items in your cart",
+		run:         orderPlace,
+	},
+	{
+		Name:        "reorder",
+		Usage:       "reorder <order> [-address <id>] [-card <id>] [-replace]",
+		Description: "order the items of an order again, -replace empties your cart first",
+		run:         reorder,
+	},
+	{
+		Name:        "orders list",
+		Usage:       "orders list",
+		Description: "list your orders",
+		run:         ordersList,
+	},
+}
+
+// Find returns the command named by the leading args and the remaining
+// arguments, or nil if there is no such command.
+func Find(args []string) (*Command, []string) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	if len(args) > 1 {
+		if cmd := find(args[0] + " " + args[1]); cmd != nil {
+			return cmd, args[2:]
+		}
+	}
+	if cmd := find(args[0]); cmd != nil {
+		return cmd, args[1:]
+	}
+	if name, ok := aliases[args[0]]; ok {
+		return find(name), args[1:]
+	}
+	return nil, nil
+}
+
+func find(name string) *Command {
+	for i := range Commands {
+		if Commands[i].Name == name {
+			return &Commands[i]
+		}
+	}
+	return nil
+}
+
+func (cmd *Command) Run(ctx context.Context, c Context, args []string) error {
+	return cmd.run(ctx, c, args)
+}
+
+func PrintUsage(w io.Writer) {
+	fmt.Fprintln(w, "commands:")
+	table := newTable(w)
+	for _, cmd := range Commands {
+		fmt.Fprintf(table, "  %s\t%s\n", cmd.Usage, cmd.Description)
+	}
+	table.Flush()
+}
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
+
+func formatUSD(cents int64) string {
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
+
+func productsList(ctx context.Context, c Context, args []string) error {
+	products, err := c.Client.Product.List(ctx)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		return output.Write(c.Out, "products", output.FromProducts(products.Data))
+	}
+
+	table := newTable(c.Out)
+	fmt.Fprintln(table, "VARIANT\tPRODUCT\tVARIANT NAME\tPRICE\tSUBSCRIPTION")
+	for _, product := range products.Data {
+		for _, variant := range product.Variants {
+			fmt.Fprintf(
+				table,
+				"%s\t%s\t%s\t%s\t%s\n",
+				variant.ID,
+				product.Name,
+				strings.ToLower(variant.Name),
+				formatUSD(variant.Price),
+				product.Subscription,
+			)
+		}
+	}
+	return table.Flush()
+}
+
+func printCart(ctx context.Context, c Context, cart terminal.Cart) error {
+	extras, err := api.GetCartExtras(ctx, c.Client)
+	if err != nil {
+		return err
+	}
+	discount := extras.Discount
+	if c.JSON {
+		return output.Write(c.Out, "cart", output.FromCart(cart, discount))
+	}
+
+	products, err := c.Client.Product.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]string{}
+	for _, product := range products.Data {
+		for _, variant := range product.Variants {
+			names[variant.ID] = product.Name + " (" + strings.ToLower(variant.Name) + ")"
+		}
+	}
+
+	table := newTable(c.Out)
+	fmt.Fprintln(table, "VARIANT\tPRODUCT\tQTY\tSUBTOTAL")
+	for _, item := range cart.Items {
+		if item.Quantity == 0 {
+			continue
+		}
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%d\t%s\n",
+			item.ProductVariantID,
+			names[item.ProductVariantID],
+			item.Quantity,
+			formatUSD(item.Subtotal),
+		)
+	}
+	fmt.Fprintln(table, "")
+	fmt.Fprintf(table, "subtotal\t\t\t%s\n", formatUSD(cart.Amount.Subtotal))
+	fmt.Fprintf(table, "shipping\t\t\t%s\n", formatUSD(cart.Amount.Shipping))
+	if discount > 0 {
+		fmt.Fprintf(table, "discount\t\t\t-%s\n", formatUSD(discount))
+	}
+	fmt.Fprintf(table, "total\t\t\t%s\n", formatUSD(cart.Amount.Subtotal+cart.Amount.Shipping-discount))
+	return table.Flush()
+}
+
+func cartShow(ctx context.Context, c Context, args []string) error {
+	cart, err := c.Client.Cart.Get(ctx)
+	if err != nil {
+		return err
+	}
+	return printCart(ctx, c, cart.Data)
+}
+
+func cartAdd(ctx context.Context, c Context, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: cart add <variant> <qty>")
+	}
+
+	productVariantID := args[0]
+	quantity, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q", args[1])
+	}
+
+	cart, err := c.Client.Cart.Get(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range cart.Data.Items {
+		if item.ProductVariantID == productVariantID {
+			quantity += item.Quantity
+		}
+	}
+	if quantity < 0 {
+		quantity = 0
+	}
+
+	params := terminal.CartSetItemParams{
+		ProductVariantID: terminal.String(productVariantID),
+		Quantity:         terminal.Int(quantity),
+	}
+	updated, err := c.Client.Cart.SetItem(ctx, params)
+	if err != nil {
+		return err
+	}
+	return printCart(ctx, c, updated.Data)
+}
+
+func addressList(ctx context.Context, c Context, args []string) error {
+	addresses, err := c.Client.Address.List(ctx)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		return output.Write(c.Out, "addresses", output.FromAddresses(addresses.Data))
+	}
+
+	table := newTable(c.Out)
+	fmt.Fprintln(table, "ID\tNAME\tADDRESS")
+	for _, address := range addresses.Data {
+		street := address.Street1
+		if address.Street2 != "" {
+			street += ", " + address.Street2
+		}
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s, %s, %s, %s %s\n",
+			address.ID,
+			address.Name,
+			street,
+			address.City,
+			address.Province,
+			address.Country,
+			address.Zip,
+		)
+	}
+	return table.Flush()
+}
+
+func cardList(ctx context.Context, c Context, args []string) error {
+	cards, err := c.Client.Card.List(ctx)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		return output.Write(c.Out, "cards", output.FromCards(cards.Data))
+	}
+
+	table := newTable(c.Out)
+	fmt.Fprintln(table, "ID\tBRAND\tNUMBER\tEXPIRES")
+	for _, card := range cards.Data {
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t**** %s\t%02d/%02d\n",
+			card.ID,
+			card.Brand,
+			card.Last4,
+			card.Expiration.Month,
+			card.Expiration.Year%100,
+		)
+	}
+	return table.Flush()
+}
+
+func orderPlace(ctx context.Context, c Context, args []string) error {
+	flags := flag.NewFlagSet("order place", flag.ContinueOnError)
+	flags.SetOutput(c.Err)
+	addressID := flags.String("address", "", "shipping address id, defaults to the one set on the cart")
+	cardID := flags.String("card", "", "card id, defaults to the one set on the cart")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return placeOrder(ctx, c, *addressID, *cardID)
+}
+
+func placeOrder(ctx context.Context, c Context, addressID string, cardID string) error {
+	if addressID != "" {
+		params := terminal.CartSetAddressParams{AddressID: terminal.F(addressID)}
+		if _, err := c.Client.Cart.SetAddress(ctx, params); err != nil {
+			return err
+		}
+	}
+	if cardID != "" {
+		params := terminal.CartSetCardParams{CardID: terminal.F(cardID)}
+		if _, err := c.Client.Cart.SetCard(ctx, params); err != nil {
+			return err
+		}
+	}
+
+	order, err := c.Client.Cart.Convert(ctx)
+	if err != nil {
+		return err
+	}
+	discount, err := api.GetOrderDiscount(ctx, c.Client, order.Data.ID)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		return output.Write(c.Out, "order", output.FromOrder(order.Data, discount))
+	}
+
+	fmt.Fprintf(
+		c.Out,
+		"placed order %s for %s\n",
+		order.Data.ID,
+		formatUSD(order.Data.Amount.Subtotal+order.Data.Amount.Shipping-discount),
+	)
+	return nil
+}
+
+func reorder(ctx context.Context, c Context, args []string) error {
+	usage := errors.New("usage: reorder <order> [-address <id>] [-card <id>] [-replace]")
+
+	flags := flag.NewFlagSet("reorder", flag.ContinueOnError)
+	flags.SetOutput(c.Err)
+	addressID := flags.String("address", "", "shipping address id, defaults to the one set on the cart")
+	cardID := flags.String("card", "", "card id, defaults to the one set on the cart")
+	replace := flags.Bool("replace", false, "remove the items and promo code already in the cart")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	orderID := flags.Arg(0)
+	if orderID == "" {
+		return usage
+	}
+	// parsing stops at the order id, flags can come after it too
+	if err := flags.Parse(flags.Args()[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usage
+	}
+
+	order, err := c.Client.Order.Get(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	quantities := map[string]int64{}
+	for _, item := range order.Data.Items {
+		if item.ProductVariantID == "" {
+			continue
+		}
+		quantities[item.ProductVariantID] += item.Quantity
+	}
+	if len(quantities) == 0 {
+		return fmt.Errorf("order %s has no items that can be reordered", orderID)
+	}
+
+	cart, err := c.Client.Cart.Get(ctx)
+	if err != nil {
+		return err
+	}
+	extras, err := api.GetCartExtras(ctx, c.Client)
+	if err != nil {
+		return err
+	}
+
+	// the cart is what gets ordered, don't buy or throw away what's already
+	// in it unless asked to
+	for _, item := range cart.Data.Items {
+		if item.Quantity == 0 {
+			continue
+		}
+		if !*replace {
+			return errors.New("your cart isn't empty, reorder with -replace to replace its items")
+		}
+		if _, ok := quantities[item.ProductVariantID]; !ok {
+			quantities[item.ProductVariantID] = 0
+		}
+	}
+	if extras.Promo != nil {
+		if !*replace {
+			return fmt.Errorf("your cart has promo code %s applied, reorder with -replace to remove it", extras.Promo.Code)
+		}
+		if err := api.RemoveCartPromo(ctx, c.Client); err != nil {
+			return err
+		}
+	}
+
+	for productVariantID, quantity := range quantities {
+		params := terminal.CartSetItemParams{
+			ProductVariantID: terminal.String(productVariantID),
+			Quantity:         terminal.Int(quantity),
+		}
+		if _, err := c.Client.Cart.SetItem(ctx, params); err != nil {
+			return err
+		}
+	}
+
+	// setting the address again quotes shipping for the new items
+	if *addressID == "" {
+		*addressID = cart.Data.AddressID
+	}
+	return placeOrder(ctx, c, *addressID, *cardID)
+}
+
+func ordersList(ctx context.Context, c Context, args []string) error {
+	orders, err := c.Client.Order.List(ctx)
+	if err != nil {
+		return err
+	}
+	discounts, err := api.GetOrderDiscounts(ctx, c.Client)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		return output.Write(c.Out, "orders", output.FromOrders(orders.Data, discounts))
+	}
+
+	table := newTable(c.Out)
+	fmt.Fprintln(table, "ID\tITEMS\tTOTAL\tTRACKING")
+	for _, order := range orders.Data {
+		count := int64(0)
+		for _, item := range order.Items {
+			count += item.Quantity
+		}
+		tracking := "-"
+		if order.Tracking.Number != "" {
+			tracking = order.Tracking.Service + " " + order.Tracking.Number
+		}
+		fmt.Fprintf(
+			table,
+			"%s\t%d\t%s\t%s\n",
+			order.ID,
+			count,
+			formatUSD(order.Amount.Subtotal+order.Amount.Shipping-discounts[order.ID]),
+			tracking,
+		)
+	}
+	return table.Flush()
+}
+
+func subscriptionsList(ctx context.Context, c Context, args []string) error {
+	subscriptions, err := c.Client.Subscription.List(ctx)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		return output.Write(c.Out, "subscriptions", output.FromSubscriptions(subscriptions.Data))
+	}
+
+	table := newTable(c.Out)
+	fmt.Fprintln(table, "ID\tVARIANT\tQTY\tFREQUENCY")
+	for _, subscription := range subscriptions.Data {
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%d\t%s\n",
+			subscription.ID,
+			subscription.ProductVariantID,
+			subscription.Quantity,
+			subscription.Frequency,
+		)
+	}
+	return table.Flush()
+}
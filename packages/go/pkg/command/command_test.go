@@ -0,0 +1,76 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/command"
+	"github.com/terminaldotshop/terminal/go/pkg/fakeapi"
+)
+
+func run(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	fake := fakeapi.New(fakeapi.DefaultSeed())
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client := terminal.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithBearerToken(fake.SignIn("SHA256:test")),
+	)
+
+	cmd, args := command.Find(args)
+	if cmd == nil {
+		t.Fatalf("no command for %v", args)
+	}
+	var out, errOut bytes.Buffer
+	err := cmd.Run(context.Background(), command.Context{Client: client, Out: &out, Err: &errOut}, args)
+	return out.String(), errOut.String(), err
+}
+
+func TestReorderArgumentOrder(t *testing.T) {
+	for _, args := range [][]string{
+		{"reorder", "-replace", "-card", "crd_visa", "-address", "shp_home", "ord_01J1KQX8Y0000000000000000"},
+		{"reorder", "ord_01J1KQX8Y0000000000000000", "-replace", "-card", "crd_visa", "-address", "shp_home"},
+		{"reorder", "-replace", "ord_01J1KQX8Y0000000000000000", "-card", "crd_visa", "-address", "shp_home"},
+	} {
+		out, _, err := run(t, args...)
+		if err != nil {
+			t.Errorf("%v: %v", args, err)
+			continue
+		}
+		if !strings.HasPrefix(out, "placed order ") {
+			t.Errorf("%v: unexpected output %q", args, out)
+		}
+	}
+}
+
+func TestReorderUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"reorder"},
+		{"reorder", "-replace"},
+		{"reorder", "ord_01J1KQX8Y0000000000000000", "ord_01J1KQX8Y0000000000000000"},
+	} {
+		if _, _, err := run(t, args...); err == nil || !strings.HasPrefix(err.Error(), "usage: reorder") {
+			t.Errorf("%v: expected the usage, got %v", args, err)
+		}
+	}
+}
+
+func TestFlagErrorsOnStderr(t *testing.T) {
+	out, errOut, err := run(t, "reorder", "-bogus", "ord_01J1KQX8Y0000000000000000")
+	if err == nil {
+		t.Fatal("expected an error for an unknown flag")
+	}
+	if out != "" {
+		t.Errorf("expected nothing on stdout, got %q", out)
+	}
+	if !strings.Contains(errOut, "-bogus") {
+		t.Errorf("expected the flag error on stderr, got %q", errOut)
+	}
+}
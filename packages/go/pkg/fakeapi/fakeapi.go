@@ -0,0 +1,1102 @@
+// Package fakeapi is an in-memory stand-in for the Terminal API and its auth
+// server, used to run the shop and its tests without an SST environment.
+//
+// The API is served at the root and the auth server under /auth, so point
+// SST_RESOURCE_Api at http://host and SST_RESOURCE_Auth at http://host/auth.
+// Every fingerprint that signs in gets its own copy of the seed data.
+package fakeapi
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+var freeShipping = api.FreeShipping{Threshold: 40 * 100, Country: "US"}
+
+// EmailCode signs in any email, no code is actually sent.
+const EmailCode = "000000"
+
+type Server struct {
+	mu       sync.Mutex
+	seed     Seed
+	users    map[string]*user
+	tokens   map[string]*user
+	pairings map[string]pairing
+	// failing holds the patterns that answer with a server error, see Fail
+	failing map[string]bool
+	mux     *http.ServeMux
+}
+
+type user struct {
+	Seed
+	// secrets holds the unobfuscated personal access tokens by ID
+	secrets map[string]string
+	// keys holds the creation time of the linked fingerprints
+	keys map[string]string
+	// defaults isn't on the SDK's profile yet, see profile
+	defaults api.Defaults
+	// promo isn't on the SDK's cart yet, see cart
+	promo *api.Promo
+	// shippingMethod is the ID of the chosen shipping rate, empty for the
+	// cheapest
+	shippingMethod string
+	// discounts isn't on the SDK's orders yet, see order
+	discounts map[string]int64
+}
+
+type profileUser struct {
+	terminal.ProfileUser
+	api.Defaults
+}
+
+type profile struct {
+	User profileUser `json:"user"`
+}
+
+func (u *user) profile() profile {
+	return profile{User: profileUser{u.Profile.User, u.defaults}}
+}
+
+// amount is the SDK's cart and order amount with the discount.
+type amount struct {
+	Subtotal int64 `json:"subtotal"`
+	Shipping int64 `json:"shipping"`
+	Discount int64 `json:"discount,omitempty"`
+}
+
+type cart struct {
+	terminal.Cart
+	Amount       amount           `json:"amount"`
+	Promo        *api.Promo       `json:"promo,omitempty"`
+	FreeShipping api.FreeShipping `json:"freeShipping"`
+}
+
+func (u *user) cart() cart {
+	discount := int64(0)
+	if u.promo != nil {
+		discount = u.promo.Discount(u.Cart.Amount.Subtotal)
+	}
+	total := amount{u.Cart.Amount.Subtotal, u.Cart.Amount.Shipping, discount}
+	return cart{u.Cart, total, u.promo, freeShipping}
+}
+
+type order struct {
+	terminal.Order
+	Amount amount `json:"amount"`
+}
+
+func (u *user) order(o terminal.Order) order {
+	return order{o, amount{o.Amount.Subtotal, o.Amount.Shipping, u.discounts[o.ID]}}
+}
+
+type httpError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func badRequest(message string) error {
+	return &httpError{status: http.StatusBadRequest, code: "input", message: message}
+}
+
+func notFound(message string) error {
+	return &httpError{status: http.StatusNotFound, code: "not_found", message: message}
+}
+
+type handler = func(u *user, r *http.Request) (interface{}, error)
+
+func New(seed Seed) *Server {
+	s := &Server{
+		seed:     seed,
+		users:    map[string]*user{},
+		tokens:   map[string]*user{},
+		pairings: map[string]pairing{},
+		failing:  map[string]bool{},
+		mux:      http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("POST /auth/token", s.authToken)
+	s.mux.HandleFunc("POST /auth/ssh/code", s.authCode)
+
+	s.handle("GET /view/init", s.viewInit)
+	s.handle("GET /product", s.productList)
+	s.handle("GET /product/{id}", s.productGet)
+	s.handle("GET /profile", s.profileMe)
+	s.handle("PUT /profile", s.profileUpdate)
+	s.handle("GET /address", s.addressList)
+	s.handle("POST /address", s.addressNew)
+	s.handle("GET /address/{id}", s.addressGet)
+	s.handle("PUT /address/{id}", s.addressUpdate)
+	s.handle("DELETE /address/{id}", s.addressDelete)
+	s.handle("GET /card", s.cardList)
+	s.handle("POST /card", s.cardNew)
+	s.handle("GET /card/{id}", s.cardGet)
+	s.handle("DELETE /card/{id}", s.cardDelete)
+	s.handle("GET /cart", s.cartGet)
+	s.handle("PUT /cart/item", s.cartSetItem)
+	s.handle("PUT /cart/address", s.cartSetAddress)
+	s.handle("GET /cart/shipping", s.cartShippingList)
+	s.handle("PUT /cart/shipping", s.cartSetShipping)
+	s.handle("PUT /cart/card", s.cartSetCard)
+	s.handle("PUT /cart/promo", s.cartSetPromo)
+	s.handle("DELETE /cart/promo", s.cartRemovePromo)
+	s.handle("POST /cart/convert", s.cartConvert)
+	s.handle("GET /order", s.orderList)
+	s.handle("GET /order/{id}", s.orderGet)
+	s.handle("GET /subscription", s.subscriptionList)
+	s.handle("POST /subscription", s.subscriptionNew)
+	s.handle("PUT /subscription/{id}", s.subscriptionUpdate)
+	s.handle("POST /subscription/{id}/pause", s.subscriptionPause)
+	s.handle("POST /subscription/{id}/resume", s.subscriptionResume)
+	s.handle("POST /subscription/{id}/skip", s.subscriptionSkip)
+	s.handle("DELETE /subscription/{id}", s.subscriptionDelete)
+	s.handle("GET /token", s.tokenList)
+	s.handle("POST /token", s.tokenNew)
+	s.handle("GET /token/{id}", s.tokenGet)
+	s.handle("DELETE /token/{id}", s.tokenDelete)
+	s.handle("GET /key", s.keyList)
+	s.handle("POST /key/pair", s.keyPair)
+	s.handle("DELETE /key/{id}", s.keyDelete)
+	s.handle("GET /app", s.appList)
+	s.handle("POST /app", s.appNew)
+	s.handle("GET /app/{id}", s.appGet)
+	s.handle("DELETE /app/{id}", s.appDelete)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Fail makes every request to pattern, as registered with handle, fail. It
+// answers with a 400 rather than a 500 so the SDK doesn't retry.
+func (s *Server) Fail(pattern string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing[pattern] = true
+}
+
+// SignIn returns an access token for fingerprint, creating the user from the
+// seed data on first use.
+func (s *Server) SignIn(fingerprint string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[fingerprint]
+	if !ok {
+		u = &user{Seed: s.seed.copy(), secrets: map[string]string{}, keys: map[string]string{}, discounts: map[string]int64{}}
+		u.Profile.User.ID = newID("usr")
+		u.Profile.User.Fingerprint = fingerprint
+		s.link(fingerprint, u)
+	}
+
+	token := "fake_" + random(24)
+	s.tokens[token] = u
+	return token
+}
+
+func (s *Server) authCode(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("email") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid_request",
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+func (s *Server) authToken(w http.ResponseWriter, r *http.Request) {
+	if email := r.PostFormValue("email"); email != "" {
+		if r.PostFormValue("code") != EmailCode {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "invalid_request",
+			})
+			return
+		}
+		token := s.SignIn("email:" + email)
+		s.mu.Lock()
+		s.tokens[token].Profile.User.Email = email
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]string{
+			"access_token":  token,
+			"refresh_token": "fake_" + random(24),
+		})
+		return
+	}
+
+	fingerprint := r.PostFormValue("fingerprint")
+	if r.PostFormValue("provider") != "ssh" || fingerprint == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "unsupported_grant_type",
+		})
+		return
+	}
+
+	// move keys from before SHA256 fingerprints over, like the auth endpoint
+	if legacy := r.PostFormValue("legacy_fingerprint"); legacy != "" {
+		s.mu.Lock()
+		if u, ok := s.users[legacy]; ok && (s.users[fingerprint] == nil || s.users[fingerprint] == u) {
+			s.migrate(legacy, fingerprint, u)
+		}
+		s.mu.Unlock()
+	}
+
+	// claim codes only sign back in, like the auth endpoint
+	if r.PostFormValue("existing") == "true" {
+		s.mu.Lock()
+		_, ok := s.users[fingerprint]
+		s.mu.Unlock()
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{
+				"error": "not_found",
+			})
+			return
+		}
+	}
+
+	if code := r.PostFormValue("pairing_code"); code != "" && !s.pair(fingerprint, code) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid_request",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token":  s.SignIn(fingerprint),
+		"refresh_token": "fake_" + random(24),
+	})
+}
+
+func (s *Server) handle(pattern string, fn handler) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		u, ok := s.tokens[token]
+		if !ok {
+			writeError(w, &httpError{
+				status:  http.StatusUnauthorized,
+				code:    "auth.invalid",
+				message: "Invalid bearer token",
+			})
+			return
+		}
+
+		if !u.usePersonalToken(token, time.Now()) {
+			writeError(w, &httpError{
+				status:  http.StatusUnauthorized,
+				code:    "auth.invalid",
+				message: "Invalid bearer token",
+			})
+			return
+		}
+
+		if s.failing[pattern] {
+			writeError(w, &httpError{
+				status:  http.StatusBadRequest,
+				code:    "internal",
+				message: "Something went wrong",
+			})
+			return
+		}
+
+		data, err := fn(u, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*httpError)
+	if !ok {
+		e = &httpError{status: http.StatusInternalServerError, code: "internal", message: err.Error()}
+	}
+	writeJSON(w, e.status, map[string]string{"code": e.code, "message": e.message})
+}
+
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("Invalid request body")
+	}
+	return nil
+}
+
+// newID returns prefix_<ULID>, like the IDs the real API hands out.
+func newID(prefix string) string {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	ms := time.Now().UnixMilli()
+	id := make([]byte, 10)
+	for i := 9; i >= 0; i-- {
+		id[i] = alphabet[ms&31]
+		ms >>= 5
+	}
+	return prefix + "_" + string(id) + random(16)
+}
+
+func random(n int) string {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	bytes := make([]byte, n)
+	rand.Read(bytes)
+	for i, b := range bytes {
+		bytes[i] = alphabet[b&31]
+	}
+	return string(bytes)
+}
+
+func (s *Server) viewInit(u *user, r *http.Request) (interface{}, error) {
+	// the real API never lists promo codes
+	seed := u.Seed
+	seed.Promos = nil
+	return seed, nil
+}
+
+func (s *Server) productList(u *user, r *http.Request) (interface{}, error) {
+	return u.Products, nil
+}
+
+func (s *Server) productGet(u *user, r *http.Request) (interface{}, error) {
+	for _, product := range u.Products {
+		if product.ID == r.PathValue("id") {
+			return product, nil
+		}
+	}
+	return nil, notFound("Product not found.")
+}
+
+func (s *Server) profileMe(u *user, r *http.Request) (interface{}, error) {
+	return u.profile(), nil
+}
+
+func (s *Server) profileUpdate(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		Name             *string `json:"name"`
+		Email            *string `json:"email"`
+		DefaultAddressID *string `json:"defaultAddressID"`
+		DefaultCardID    *string `json:"defaultCardID"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if body.Name != nil {
+		u.Profile.User.Name = *body.Name
+	}
+	if body.Email != nil {
+		if !strings.Contains(*body.Email, "@") {
+			return nil, badRequest("Invalid email")
+		}
+		u.Profile.User.Email = *body.Email
+	}
+	if body.DefaultAddressID != nil {
+		if *body.DefaultAddressID != "" && u.address(*body.DefaultAddressID) == nil {
+			return nil, badRequest("Address not found")
+		}
+		u.defaults.AddressID = *body.DefaultAddressID
+	}
+	if body.DefaultCardID != nil {
+		if *body.DefaultCardID != "" && u.card(*body.DefaultCardID) == nil {
+			return nil, badRequest("Card not found")
+		}
+		u.defaults.CardID = *body.DefaultCardID
+	}
+	return u.profile(), nil
+}
+
+func (s *Server) addressList(u *user, r *http.Request) (interface{}, error) {
+	return u.Addresses, nil
+}
+
+func decodeAddress(r *http.Request) (terminal.Address, error) {
+	address := terminal.Address{}
+	if err := decode(r, &address); err != nil {
+		return address, err
+	}
+	if address.Name == "" || address.Street1 == "" || address.City == "" ||
+		address.Country == "" || address.Zip == "" {
+		return address, badRequest("name, street1, city, country and zip are required")
+	}
+	return address, nil
+}
+
+func (s *Server) addressNew(u *user, r *http.Request) (interface{}, error) {
+	address, err := decodeAddress(r)
+	if err != nil {
+		return nil, err
+	}
+	address.ID = newID("shp")
+	u.Addresses = append(u.Addresses, address)
+	return address.ID, nil
+}
+
+func (s *Server) addressGet(u *user, r *http.Request) (interface{}, error) {
+	address := u.address(r.PathValue("id"))
+	if address == nil {
+		return nil, notFound("Address not found.")
+	}
+	return address, nil
+}
+
+func (s *Server) addressUpdate(u *user, r *http.Request) (interface{}, error) {
+	existing := u.address(r.PathValue("id"))
+	if existing == nil {
+		return nil, badRequest("Address not found")
+	}
+	address, err := decodeAddress(r)
+	if err != nil {
+		return nil, err
+	}
+	address.ID = existing.ID
+	*existing = address
+	return "ok", nil
+}
+
+func (s *Server) addressDelete(u *user, r *http.Request) (interface{}, error) {
+	addresses := []terminal.Address{}
+	for _, address := range u.Addresses {
+		if address.ID != r.PathValue("id") {
+			addresses = append(addresses, address)
+		}
+	}
+	u.Addresses = addresses
+	if u.Cart.AddressID == r.PathValue("id") {
+		u.Cart.AddressID = ""
+		u.updateShipping()
+	}
+	if u.defaults.AddressID == r.PathValue("id") {
+		u.defaults.AddressID = ""
+	}
+	return "ok", nil
+}
+
+func (s *Server) cardList(u *user, r *http.Request) (interface{}, error) {
+	return u.Cards, nil
+}
+
+// cardNew accepts any token, there is no stripe to exchange it with.
+func (s *Server) cardNew(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		Token string `json:"token"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if body.Token == "" {
+		return nil, badRequest("token is required")
+	}
+
+	card := terminal.Card{
+		ID:    newID("crd"),
+		Brand: "Visa",
+		Last4: "4242",
+	}
+	card.Expiration.Month = 12
+	card.Expiration.Year = int64(time.Now().Year() + 3)
+	u.Cards = append(u.Cards, card)
+	return card.ID, nil
+}
+
+func (s *Server) cardGet(u *user, r *http.Request) (interface{}, error) {
+	card := u.card(r.PathValue("id"))
+	if card == nil {
+		return nil, notFound("Card not found.")
+	}
+	return card, nil
+}
+
+func (s *Server) cardDelete(u *user, r *http.Request) (interface{}, error) {
+	cards := []terminal.Card{}
+	for _, card := range u.Cards {
+		if card.ID != r.PathValue("id") {
+			cards = append(cards, card)
+		}
+	}
+	u.Cards = cards
+	if u.Cart.CardID == r.PathValue("id") {
+		u.Cart.CardID = ""
+	}
+	if u.defaults.CardID == r.PathValue("id") {
+		u.defaults.CardID = ""
+	}
+	return "ok", nil
+}
+
+func (s *Server) cartGet(u *user, r *http.Request) (interface{}, error) {
+	return u.cart(), nil
+}
+
+func (s *Server) cartSetItem(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		ProductVariantID string `json:"productVariantID"`
+		Quantity         int64  `json:"quantity"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	variant := u.variant(body.ProductVariantID)
+	if variant == nil {
+		return nil, badRequest("Product variant not found")
+	}
+	if body.Quantity < 0 {
+		return nil, badRequest("Quantity must be at least 0")
+	}
+
+	items := []terminal.CartItem{}
+	found := false
+	for _, item := range u.Cart.Items {
+		if item.ProductVariantID == body.ProductVariantID {
+			found = true
+			item.Quantity = body.Quantity
+			item.Subtotal = variant.Price * body.Quantity
+		}
+		if item.Quantity > 0 {
+			items = append(items, item)
+		}
+	}
+	if !found && body.Quantity > 0 {
+		items = append(items, terminal.CartItem{
+			ID:               newID("itm"),
+			ProductVariantID: variant.ID,
+			Quantity:         body.Quantity,
+			Subtotal:         variant.Price * body.Quantity,
+		})
+	}
+	u.Cart.Items = items
+	u.updateShipping()
+	return u.cart(), nil
+}
+
+func (s *Server) cartSetAddress(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		AddressID string `json:"addressID"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if u.address(body.AddressID) == nil {
+		return nil, badRequest("address not found")
+	}
+	u.Cart.AddressID = body.AddressID
+	u.shippingMethod = ""
+	u.updateShipping()
+	return "ok", nil
+}
+
+func (s *Server) cartShippingList(u *user, r *http.Request) (interface{}, error) {
+	if u.address(u.Cart.AddressID) == nil {
+		return nil, badRequest("Set a shipping address first")
+	}
+	return u.shippingRates(), nil
+}
+
+func (s *Server) cartSetShipping(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		ShippingRateID string `json:"shippingRateID"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	for _, rate := range u.shippingRates() {
+		if rate.ID == body.ShippingRateID {
+			u.shippingMethod = rate.ID
+			u.updateShipping()
+			return "ok", nil
+		}
+	}
+	return nil, badRequest("Shipping method not available")
+}
+
+func (s *Server) cartSetCard(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		CardID string `json:"cardID"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	for _, card := range u.Cards {
+		if card.ID == body.CardID {
+			u.Cart.CardID = body.CardID
+			return "ok", nil
+		}
+	}
+	return nil, badRequest("card not found")
+}
+
+// cartSetPromo accepts any code in the seed's promos, they never expire or
+// run out.
+func (s *Server) cartSetPromo(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		Code string `json:"code"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	code := strings.ToUpper(strings.TrimSpace(body.Code))
+	for _, promo := range u.Promos {
+		if promo.Code == code {
+			u.promo = &promo
+			return promo, nil
+		}
+	}
+	return nil, badRequest("Invalid promo code")
+}
+
+func (s *Server) cartRemovePromo(u *user, r *http.Request) (interface{}, error) {
+	u.promo = nil
+	return "ok", nil
+}
+
+func (s *Server) cartConvert(u *user, r *http.Request) (interface{}, error) {
+	address := u.address(u.Cart.AddressID)
+	switch {
+	case len(u.Cart.Items) == 0:
+		return nil, badRequest("Cart is empty")
+	case address == nil:
+		return nil, badRequest("No shipping address")
+	case u.Cart.CardID == "":
+		return nil, badRequest("No payment method")
+	}
+
+	order := terminal.Order{
+		ID:    newID("ord"),
+		Index: int64(len(u.Orders)),
+		Items: []terminal.OrderItem{},
+	}
+	order.Amount.Subtotal = u.Cart.Amount.Subtotal
+	order.Amount.Shipping = u.Cart.Amount.Shipping
+	order.Shipping.Name = address.Name
+	order.Shipping.Street1 = address.Street1
+	order.Shipping.Street2 = address.Street2
+	order.Shipping.City = address.City
+	order.Shipping.Province = address.Province
+	order.Shipping.Country = address.Country
+	order.Shipping.Zip = address.Zip
+	order.Shipping.Phone = address.Phone
+	for _, item := range u.Cart.Items {
+		order.Items = append(order.Items, terminal.OrderItem{
+			ID:               newID("itm"),
+			Amount:           item.Subtotal,
+			Quantity:         item.Quantity,
+			ProductVariantID: item.ProductVariantID,
+		})
+	}
+
+	if u.promo != nil {
+		u.discounts[order.ID] = u.promo.Discount(order.Amount.Subtotal)
+	}
+	u.Orders = append(u.Orders, order)
+	u.Cart = terminal.Cart{Items: []terminal.CartItem{}}
+	u.promo = nil
+	u.shippingMethod = ""
+	return u.order(order), nil
+}
+
+func (s *Server) orderList(u *user, r *http.Request) (interface{}, error) {
+	orders := []order{}
+	for _, order := range u.Orders {
+		orders = append(orders, u.order(order))
+	}
+	return orders, nil
+}
+
+func (s *Server) orderGet(u *user, r *http.Request) (interface{}, error) {
+	for _, order := range u.Orders {
+		if order.ID == r.PathValue("id") {
+			return u.order(order), nil
+		}
+	}
+	return nil, notFound("Order not found.")
+}
+
+func (s *Server) subscriptionList(u *user, r *http.Request) (interface{}, error) {
+	return u.Subscriptions, nil
+}
+
+func (s *Server) subscriptionNew(u *user, r *http.Request) (interface{}, error) {
+	subscription := api.Subscription{}
+	if err := decode(r, &subscription); err != nil {
+		return nil, err
+	}
+	if u.variant(subscription.ProductVariantID) == nil {
+		return nil, badRequest("Product variant not found")
+	}
+	if u.address(subscription.AddressID) == nil {
+		return nil, badRequest("address not found")
+	}
+	if subscription.Quantity < 1 {
+		return nil, badRequest("Quantity must be at least 1")
+	}
+	switch subscription.Frequency {
+	case terminal.SubscriptionFrequencyFixed,
+		terminal.SubscriptionFrequencyWeekly,
+		api.SubscriptionFrequencyBiweekly,
+		terminal.SubscriptionFrequencyMonthly:
+	default:
+		return nil, badRequest("Subscription frequency must be weekly, biweekly or monthly")
+	}
+	subscription.ID = newID("sub")
+	subscription.Next = nextShipment(subscription.Frequency, time.Now()).Format(time.RFC3339)
+	subscription.Paused = false
+	u.Subscriptions = append(u.Subscriptions, subscription)
+	return "ok", nil
+}
+
+// nextShipment is the shipment after from for a subscription shipping at
+// frequency.
+func nextShipment(frequency terminal.SubscriptionFrequency, from time.Time) time.Time {
+	switch frequency {
+	case terminal.SubscriptionFrequencyDaily:
+		return from.AddDate(0, 0, 1)
+	case terminal.SubscriptionFrequencyWeekly:
+		return from.AddDate(0, 0, 7)
+	case api.SubscriptionFrequencyBiweekly:
+		return from.AddDate(0, 0, 14)
+	case terminal.SubscriptionFrequencyYearly:
+		return from.AddDate(1, 0, 0)
+	}
+	return from.AddDate(0, 1, 0)
+}
+
+func (s *Server) subscriptionUpdate(u *user, r *http.Request) (interface{}, error) {
+	subscription := u.subscription(r.PathValue("id"))
+	if subscription == nil {
+		return nil, badRequest("Subscription not found")
+	}
+	body := api.SubscriptionParams{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if body.AddressID != "" && u.address(body.AddressID) == nil {
+		return nil, badRequest("Address not found")
+	}
+	if body.CardID != "" && u.card(body.CardID) == nil {
+		return nil, badRequest("Card not found")
+	}
+	if body.ProductVariantID != "" && (u.variant(body.ProductVariantID) == nil ||
+		u.product(body.ProductVariantID) != u.product(subscription.ProductVariantID)) {
+		return nil, badRequest("Variant must be of the same product")
+	}
+	for _, other := range u.Subscriptions {
+		if other.ID != subscription.ID && body.ProductVariantID != "" &&
+			other.ProductVariantID == body.ProductVariantID {
+			return nil, badRequest("You already have a subscription for this variant")
+		}
+	}
+	if body.AddressID != "" {
+		subscription.AddressID = body.AddressID
+	}
+	if body.CardID != "" {
+		subscription.CardID = body.CardID
+	}
+	if body.ProductVariantID != "" {
+		subscription.ProductVariantID = body.ProductVariantID
+	}
+	return "ok", nil
+}
+
+func (s *Server) subscriptionPause(u *user, r *http.Request) (interface{}, error) {
+	subscription := u.subscription(r.PathValue("id"))
+	if subscription == nil {
+		return nil, badRequest("Subscription not found")
+	}
+	subscription.Paused = true
+	return "ok", nil
+}
+
+func (s *Server) subscriptionResume(u *user, r *http.Request) (interface{}, error) {
+	subscription := u.subscription(r.PathValue("id"))
+	if subscription == nil {
+		return nil, badRequest("Subscription not found")
+	}
+	if !subscription.Paused {
+		return "ok", nil
+	}
+	subscription.Paused = false
+	now := time.Now()
+	next, err := time.Parse(time.RFC3339, subscription.Next)
+	if err != nil || !next.After(now) {
+		subscription.Next = nextShipment(subscription.Frequency, now).Format(time.RFC3339)
+	}
+	return "ok", nil
+}
+
+func (s *Server) subscriptionSkip(u *user, r *http.Request) (interface{}, error) {
+	subscription := u.subscription(r.PathValue("id"))
+	if subscription == nil {
+		return nil, badRequest("Subscription not found")
+	}
+	next, err := time.Parse(time.RFC3339, subscription.Next)
+	if err != nil {
+		next = time.Now()
+	}
+	subscription.Next = nextShipment(subscription.Frequency, next).Format(time.RFC3339)
+	return "ok", nil
+}
+
+func (s *Server) subscriptionDelete(u *user, r *http.Request) (interface{}, error) {
+	subscriptions := []api.Subscription{}
+	for _, subscription := range u.Subscriptions {
+		if subscription.ID != r.PathValue("id") {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	u.Subscriptions = subscriptions
+	return "ok", nil
+}
+
+func (s *Server) tokenList(u *user, r *http.Request) (interface{}, error) {
+	return u.Tokens, nil
+}
+
+// usePersonalToken records the use of a personal access token and reports
+// whether it's still valid. Other bearer tokens are always valid.
+func (u *user) usePersonalToken(secret string, now time.Time) bool {
+	for id, value := range u.secrets {
+		if value != secret {
+			continue
+		}
+		for i, token := range u.Tokens {
+			if token.ID != id {
+				continue
+			}
+			if token.Expired(now) {
+				return false
+			}
+			u.Tokens[i].Time.Used = now.UTC().Format(time.RFC3339)
+		}
+	}
+	return true
+}
+
+// tokenNew creates a personal access token, which can be used as a bearer
+// token like the real ones. Scopes are stored but not enforced.
+func (s *Server) tokenNew(u *user, r *http.Request) (interface{}, error) {
+	params := api.TokenParams{Scope: api.TokenScopeFull}
+	if r.ContentLength != 0 {
+		if err := decode(r, &params); err != nil {
+			return nil, err
+		}
+	}
+	switch params.Scope {
+	case "":
+		params.Scope = api.TokenScopeFull
+	case api.TokenScopeRead, api.TokenScopeCart, api.TokenScopeFull:
+	default:
+		return nil, badRequest("Invalid scope")
+	}
+	if params.Expires != nil && !params.Expires.After(time.Now()) {
+		return nil, badRequest("Expiry must be in the future")
+	}
+
+	id := newID("pat")
+	secret := "trm_fake_" + random(24)
+
+	token := api.Token{
+		ID:    id,
+		Token: "trm_fake_******" + secret[len(secret)-4:],
+		Label: params.Label,
+		Scope: params.Scope,
+	}
+	token.Time.Created = time.Now().UTC().Format(time.RFC3339)
+	if params.Expires != nil {
+		token.Time.Expires = params.Expires.UTC().Format(time.RFC3339)
+	}
+	u.Tokens = append(u.Tokens, token)
+	u.secrets[id] = secret
+	s.tokens[secret] = u
+
+	return map[string]string{"id": id, "token": secret}, nil
+}
+
+func (s *Server) tokenGet(u *user, r *http.Request) (interface{}, error) {
+	for _, token := range u.Tokens {
+		if token.ID == r.PathValue("id") {
+			return token, nil
+		}
+	}
+	return nil, notFound("Personal token not found.")
+}
+
+func (s *Server) tokenDelete(u *user, r *http.Request) (interface{}, error) {
+	tokens := []api.Token{}
+	for _, token := range u.Tokens {
+		if token.ID != r.PathValue("id") {
+			tokens = append(tokens, token)
+		}
+	}
+	u.Tokens = tokens
+	delete(s.tokens, u.secrets[r.PathValue("id")])
+	delete(u.secrets, r.PathValue("id"))
+	return "ok", nil
+}
+
+func (s *Server) appList(u *user, r *http.Request) (interface{}, error) {
+	return u.Apps, nil
+}
+
+func (s *Server) appNew(u *user, r *http.Request) (interface{}, error) {
+	body := struct {
+		Name        string `json:"name"`
+		RedirectURI string `json:"redirectURI"`
+	}{}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if body.Name == "" || body.RedirectURI == "" {
+		return nil, badRequest("name and redirectURI are required")
+	}
+
+	secret := newID("sec")
+	app := terminal.App{
+		ID:          newID("cli"),
+		Name:        body.Name,
+		RedirectURI: body.RedirectURI,
+		Secret:      "******" + secret[len(secret)-4:],
+	}
+	u.Apps = append(u.Apps, app)
+	return map[string]string{"id": app.ID, "secret": secret}, nil
+}
+
+func (s *Server) appGet(u *user, r *http.Request) (interface{}, error) {
+	for _, app := range u.Apps {
+		if app.ID == r.PathValue("id") {
+			return app, nil
+		}
+	}
+	return nil, notFound("App not found.")
+}
+
+func (s *Server) appDelete(u *user, r *http.Request) (interface{}, error) {
+	apps := []terminal.App{}
+	for _, app := range u.Apps {
+		if app.ID != r.PathValue("id") {
+			apps = append(apps, app)
+		}
+	}
+	u.Apps = apps
+	return "ok", nil
+}
+
+func (u *user) address(id string) *terminal.Address {
+	for i := range u.Addresses {
+		if u.Addresses[i].ID == id {
+			return &u.Addresses[i]
+		}
+	}
+	return nil
+}
+
+func (u *user) subscription(id string) *api.Subscription {
+	for i := range u.Subscriptions {
+		if u.Subscriptions[i].ID == id {
+			return &u.Subscriptions[i]
+		}
+	}
+	return nil
+}
+
+// product is the product a variant belongs to, nil for unknown variants.
+func (u *user) product(variantID string) *terminal.Product {
+	for i := range u.Products {
+		for _, variant := range u.Products[i].Variants {
+			if variant.ID == variantID {
+				return &u.Products[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (u *user) card(id string) *terminal.Card {
+	for i := range u.Cards {
+		if u.Cards[i].ID == id {
+			return &u.Cards[i]
+		}
+	}
+	return nil
+}
+
+func (u *user) variant(id string) *terminal.ProductVariant {
+	for _, product := range u.Products {
+		for i := range product.Variants {
+			if product.Variants[i].ID == id {
+				return &product.Variants[i]
+			}
+		}
+	}
+	return nil
+}
+
+// updateShipping recalculates the cart totals the way the real API does,
+// with flat rates standing in for the carrier quotes.
+func (u *user) updateShipping() {
+	subtotal := int64(0)
+	for _, item := range u.Cart.Items {
+		subtotal += item.Subtotal
+	}
+	u.Cart.Subtotal = subtotal
+	u.Cart.Amount.Subtotal = subtotal
+
+	rates := u.shippingRates()
+	if len(rates) == 0 {
+		u.Cart.Amount.Shipping = 0
+		u.Cart.Shipping.Service = ""
+		u.Cart.Shipping.Timeframe = ""
+		return
+	}
+	rate := rates[0]
+	for _, next := range rates {
+		if next.ID == u.shippingMethod {
+			rate = next
+		}
+	}
+	u.Cart.Amount.Shipping = rate.Amount
+	u.Cart.Shipping.Service = rate.Service
+	u.Cart.Shipping.Timeframe = rate.Timeframe
+}
+
+// shippingRates are the methods for the cart's address, cheapest first. Like
+// the real API, US orders get flat rate ground shipping.
+func (u *user) shippingRates() []api.ShippingRate {
+	address := u.address(u.Cart.AddressID)
+	switch {
+	case address == nil:
+		return nil
+	case address.Country == freeShipping.Country:
+		ground := int64(800)
+		if u.Cart.Subtotal >= freeShipping.Threshold {
+			ground = 0
+		}
+		return []api.ShippingRate{
+			{ID: "usps_ground_advantage", Service: "USPS Ground Advantage", Amount: ground, Timeframe: "3-5 days"},
+			{ID: "usps_priority", Service: "USPS Priority Mail", Amount: 1200, Timeframe: "1-3 days"},
+			{ID: "usps_priority_express", Service: "USPS Priority Mail Express", Amount: 3500, Timeframe: "1-2 days"},
+		}
+	}
+	return []api.ShippingRate{
+		{ID: "dhl_express_worldwide", Service: "DHL Express Worldwide", Amount: 2500, Timeframe: "4-7 days"},
+		{ID: "fedex_international_priority", Service: "FedEx International Priority", Amount: 4500, Timeframe: "1-3 days"},
+	}
+}
@@ -0,0 +1,106 @@
+package fakeapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+const pairingTTL = 10 * time.Minute
+
+type pairing struct {
+	user    *user
+	expires time.Time
+}
+
+func keyID(fingerprint string) string {
+	hash := sha256.Sum256([]byte(fingerprint))
+	return "key_" + hex.EncodeToString(hash[:])[:26]
+}
+
+func normalizePairingCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToUpper(code))
+}
+
+// link signs fingerprint in to u from now on. Callers hold s.mu.
+func (s *Server) link(fingerprint string, u *user) {
+	s.users[fingerprint] = u
+	// email sign ins aren't keys, they only reuse the map
+	if strings.HasPrefix(fingerprint, "email:") {
+		return
+	}
+	if _, ok := u.keys[fingerprint]; !ok {
+		u.keys[fingerprint] = time.Now().UTC().Format(time.RFC3339)
+	}
+}
+
+// migrate moves the key u knows by its MD5 fingerprint to its SHA256 one.
+func (s *Server) migrate(legacy string, fingerprint string, u *user) {
+	delete(s.users, legacy)
+	s.users[fingerprint] = u
+	if created, ok := u.keys[legacy]; ok {
+		u.keys[fingerprint] = created
+		delete(u.keys, legacy)
+	}
+}
+
+// pair links fingerprint to the account that created code. Unlike the real
+// auth server, the key's old account is left behind instead of merged.
+func (s *Server) pair(fingerprint string, code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code = normalizePairingCode(code)
+	p, ok := s.pairings[code]
+	delete(s.pairings, code)
+	if !ok || time.Now().After(p.expires) {
+		return false
+	}
+	if old, ok := s.users[fingerprint]; ok {
+		delete(old.keys, fingerprint)
+	}
+	s.link(fingerprint, p.user)
+	return true
+}
+
+func (s *Server) keyList(u *user, r *http.Request) (interface{}, error) {
+	keys := []api.Key{}
+	for fingerprint, created := range u.keys {
+		keys = append(keys, api.Key{
+			ID:          keyID(fingerprint),
+			Fingerprint: fingerprint,
+			Time:        api.KeyTime{Created: created},
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Time.Created+keys[i].ID < keys[j].Time.Created+keys[j].ID
+	})
+	return keys, nil
+}
+
+func (s *Server) keyPair(u *user, r *http.Request) (interface{}, error) {
+	code := random(4) + "-" + random(4)
+	expires := time.Now().Add(pairingTTL)
+	s.pairings[normalizePairingCode(code)] = pairing{user: u, expires: expires}
+	return api.KeyPairing{Code: code, Expires: expires.UTC().Format(time.RFC3339)}, nil
+}
+
+func (s *Server) keyDelete(u *user, r *http.Request) (interface{}, error) {
+	for fingerprint := range u.keys {
+		if keyID(fingerprint) != r.PathValue("id") {
+			continue
+		}
+		if len(u.keys) == 1 {
+			return nil, badRequest("Link another key before removing the last one")
+		}
+		delete(u.keys, fingerprint)
+		delete(s.users, fingerprint)
+		return "ok", nil
+	}
+	return nil, badRequest("Key not found")
+}
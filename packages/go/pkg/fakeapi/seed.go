@@ -0,0 +1,66 @@
+package fakeapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+//go:embed seed.json
+var defaultSeed []byte
+
+// Seed is the data every user starts with. It uses the same shape as the
+// view/init response, so a fixture can be captured from the real API.
+type Seed struct {
+	Profile       terminal.Profile   `json:"profile"`
+	Products      []terminal.Product `json:"products"`
+	Addresses     []terminal.Address `json:"addresses"`
+	Cards         []terminal.Card    `json:"cards"`
+	Cart          terminal.Cart      `json:"cart"`
+	Orders        []terminal.Order   `json:"orders"`
+	Subscriptions []api.Subscription `json:"subscriptions"`
+	Tokens        []api.Token        `json:"tokens"`
+	Apps          []terminal.App     `json:"apps"`
+	// Promos are the codes any user can apply to their cart.
+	Promos []api.Promo `json:"promos"`
+}
+
+// DefaultSeed returns the fixtures bundled with the package.
+func DefaultSeed() Seed {
+	seed, err := ParseSeed(defaultSeed)
+	if err != nil {
+		panic(err)
+	}
+	return seed
+}
+
+// LoadSeed reads fixtures from a JSON file.
+func LoadSeed(path string) (Seed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Seed{}, err
+	}
+	return ParseSeed(data)
+}
+
+func ParseSeed(data []byte) (Seed, error) {
+	seed := Seed{}
+	err := json.Unmarshal(data, &seed)
+	return seed, err
+}
+
+// copy returns a deep copy so users can't see each other's changes.
+func (s Seed) copy() Seed {
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	result, err := ParseSeed(data)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+// Package metrics collects what shoppers are doing across SSH sessions, for
+// the /metrics endpoint of the SSH server.
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var Default = NewRegistry()
+
+var (
+	Sessions = Default.Gauge(
+		"terminal_ssh_sessions",
+		"SSH sessions currently open.",
+	)
+	SessionsByPage = Default.Gauge(
+		"terminal_ssh_sessions_by_page",
+		"Shop sessions currently on each page.",
+		"page",
+	)
+	Funnel = Default.Counter(
+		"terminal_checkout_funnel_total",
+		"Shop sessions that reached each step of the checkout.",
+		"step",
+	)
+	APIDuration = Default.Histogram(
+		"terminal_api_request_duration_seconds",
+		"Latency of Terminal API calls.",
+		[]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		"method", "path", "status",
+	)
+	AuthFailures = Default.Counter(
+		"terminal_auth_failures_total",
+		"Failed attempts to sign a shopper in.",
+		"reason",
+	)
+	Rejections = Default.Counter(
+		"terminal_ssh_rejections_total",
+		"SSH sessions turned away for going over a limit.",
+		"reason",
+	)
+)
+
+// FunnelSteps are the pages counted by Funnel, in checkout order.
+var FunnelSteps = []string{"shop", "cart", "shipping", "delivery", "payment", "confirm", "final"}
+
+// ObserveAPI records a call in APIDuration. IDs in the path are collapsed
+// so every address or order doesn't get its own series.
+func ObserveAPI(method string, path string, status int, duration time.Duration) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.Contains(segment, "_") {
+			segments[i] = "{id}"
+		}
+	}
+
+	statusLabel := "error"
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	APIDuration.Observe(duration.Seconds(), method, strings.Join(segments, "/"), statusLabel)
+}
+
+// Session tracks the page a shopper is on. Methods are safe on a nil
+// Session, which tracks nothing.
+type Session struct {
+	mu      sync.Mutex
+	page    string
+	reached map[string]bool
+	closed  bool
+}
+
+func NewSession() *Session {
+	return &Session{reached: map[string]bool{}}
+}
+
+func (s *Session) SetPage(page string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.page == page {
+		return
+	}
+	if s.page != "" {
+		SessionsByPage.Dec(s.page)
+	}
+	SessionsByPage.Inc(page)
+	s.page = page
+
+	for _, step := range FunnelSteps {
+		if step == page && !s.reached[page] {
+			s.reached[page] = true
+			Funnel.Inc(page)
+		}
+	}
+}
+
+func (s *Session) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	if s.page != "" {
+		SessionsByPage.Dec(s.page)
+	}
+	s.closed = true
+}
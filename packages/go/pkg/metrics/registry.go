@@ -0,0 +1,222 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds metric families and writes them in the Prometheus text
+// exposition format. It implements http.Handler for /metrics.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+type kind string
+
+const (
+	counter   kind = "counter"
+	gauge     kind = "gauge"
+	histogram kind = "histogram"
+)
+
+type family struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	kind    kind
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	values []string
+	value  float64
+	// histograms only
+	counts []uint64
+	count  uint64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(f *family) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f.series = map[string]*series{}
+	r.families = append(r.families, f)
+	return f
+}
+
+// get returns the series for the label values, creating it on first use.
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: values}
+		if f.kind == histogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+type Counter struct{ f *family }
+
+func (r *Registry) Counter(name string, help string, labels ...string) *Counter {
+	return &Counter{r.register(&family{name: name, help: help, kind: counter, labels: labels})}
+}
+
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *Counter) Add(delta float64, values ...string) {
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(values).value += delta
+}
+
+type Gauge struct{ f *family }
+
+func (r *Registry) Gauge(name string, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(&family{name: name, help: help, kind: gauge, labels: labels})}
+}
+
+func (g *Gauge) Inc(values ...string) {
+	g.Add(1, values...)
+}
+
+func (g *Gauge) Dec(values ...string) {
+	g.Add(-1, values...)
+}
+
+func (g *Gauge) Add(delta float64, values ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(values).value += delta
+}
+
+type Histogram struct{ f *family }
+
+// Histogram creates a histogram with the given upper bounds, in increasing
+// order. The +Inf bucket is implied.
+func (r *Registry) Histogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{r.register(&family{
+		name:    name,
+		help:    help,
+		kind:    histogram,
+		labels:  labels,
+		buckets: buckets,
+	})}
+}
+
+func (h *Histogram) Observe(value float64, values ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	s := h.f.get(values)
+	for i, bound := range h.f.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.Write(w)
+}
+
+// Write writes every family, with series sorted by label values so the
+// output is stable.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family{}, r.families...)
+	r.mu.Unlock()
+
+	for _, f := range families {
+		if err := f.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *family) write(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.kind)
+	for _, key := range keys {
+		s := f.series[key]
+		labels := f.labelPairs(s.values)
+		if f.kind != histogram {
+			fmt.Fprintf(&b, "%s%s %s\n", f.name, braces(labels), formatFloat(s.value))
+			continue
+		}
+
+		for i, bound := range f.buckets {
+			le := append(labels, pair("le", formatFloat(bound)))
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, braces(le), s.counts[i])
+		}
+		le := append(labels, pair("le", "+Inf"))
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, braces(le), s.count)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", f.name, braces(labels), formatFloat(s.value))
+		fmt.Fprintf(&b, "%s_count%s %d\n", f.name, braces(labels), s.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (f *family) labelPairs(values []string) []string {
+	pairs := make([]string, 0, len(f.labels)+1)
+	for i, label := range f.labels {
+		pairs = append(pairs, pair(label, values[i]))
+	}
+	return pairs
+}
+
+func pair(label string, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+	return label + `="` + value + `"`
+}
+
+func braces(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	requests := r.Counter("requests_total", "Requests served.", "path")
+	open := r.Gauge("sessions_open", "Sessions open right now.")
+	latency := r.Histogram("latency_seconds", "Request latency.", []float64{.1, 1}, "method")
+
+	requests.Inc("/b")
+	requests.Add(2, `/a "quoted" \ path`+"\n")
+	open.Inc()
+	open.Inc()
+	open.Dec()
+	latency.Observe(.05, "GET")
+	latency.Observe(.5, "GET")
+	latency.Observe(5, "GET")
+
+	var got strings.Builder
+	if err := r.Write(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{path="/a \"quoted\" \\ path\n"} 2
+requests_total{path="/b"} 1
+# HELP sessions_open Sessions open right now.
+# TYPE sessions_open gauge
+sessions_open 1
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="GET",le="0.1"} 1
+latency_seconds_bucket{method="GET",le="1"} 2
+latency_seconds_bucket{method="GET",le="+Inf"} 3
+latency_seconds_sum{method="GET"} 5.55
+latency_seconds_count{method="GET"} 3
+`
+	if got.String() != want {
+		t.Errorf("exposition changed:\n%s\nwant:\n%s", got.String(), want)
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("requests_total", "Requests served.").Inc()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if content := w.Header().Get("Content-Type"); !strings.HasPrefix(content, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", content)
+	}
+	if !strings.Contains(w.Body.String(), "requests_total 1\n") {
+		t.Errorf("expected the counter in the body, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,306 @@
+// Package output defines the machine-readable JSON documents printed by the
+// CLI with -output json.
+//
+// Every document is wrapped in an envelope:
+//
+//	{"version": 1, "kind": "<kind>", "data": <payload>}
+//
+// kind is one of "products", "cart", "order", "orders", "subscriptions",
+// "addresses", "cards" or "error". "products", "orders", "subscriptions",
+// "addresses" and "cards" carry an array, the others a single object. Errors
+// are written to stderr.
+//
+// Field names and types in a version never change. Fields may be added, but
+// removing or renaming one bumps the version. All amounts are integer cents
+// (USD) and all times are RFC 3339 strings in UTC.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+const Version = 1
+
+type Document struct {
+	Version int         `json:"version"`
+	Kind    string      `json:"kind"`
+	Data    interface{} `json:"data"`
+}
+
+type Variant struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price int64  `json:"price"`
+}
+
+type Product struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Subscription is "", "allowed" or "required".
+	Subscription string            `json:"subscription"`
+	Tags         map[string]string `json:"tags"`
+	Variants     []Variant         `json:"variants"`
+}
+
+type Amount struct {
+	Subtotal int64 `json:"subtotal"`
+	Shipping int64 `json:"shipping"`
+	// Discount is what a promo code takes off, it's left out without one.
+	Discount int64 `json:"discount,omitempty"`
+	Total    int64 `json:"total"`
+}
+
+type CartItem struct {
+	ID               string `json:"id"`
+	ProductVariantID string `json:"productVariantID"`
+	Quantity         int64  `json:"quantity"`
+	Subtotal         int64  `json:"subtotal"`
+}
+
+type CartShipping struct {
+	Service   string `json:"service"`
+	Timeframe string `json:"timeframe"`
+}
+
+type Cart struct {
+	Items     []CartItem   `json:"items"`
+	Amount    Amount       `json:"amount"`
+	AddressID string       `json:"addressID"`
+	CardID    string       `json:"cardID"`
+	Shipping  CartShipping `json:"shipping"`
+}
+
+type Address struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Street1  string `json:"street1"`
+	Street2  string `json:"street2"`
+	City     string `json:"city"`
+	Province string `json:"province"`
+	Country  string `json:"country"`
+	Zip      string `json:"zip"`
+	Phone    string `json:"phone"`
+}
+
+type Card struct {
+	ID    string `json:"id"`
+	Brand string `json:"brand"`
+	Last4 string `json:"last4"`
+	// Expiration is formatted as MM/YY.
+	Expiration string `json:"expiration"`
+}
+
+type OrderItem struct {
+	ID               string `json:"id"`
+	ProductVariantID string `json:"productVariantID"`
+	Description      string `json:"description"`
+	Quantity         int64  `json:"quantity"`
+	Amount           int64  `json:"amount"`
+}
+
+type Tracking struct {
+	Service string `json:"service"`
+	Number  string `json:"number"`
+	URL     string `json:"url"`
+}
+
+type Order struct {
+	ID       string      `json:"id"`
+	Index    int64       `json:"index"`
+	Created  string      `json:"created"`
+	Items    []OrderItem `json:"items"`
+	Amount   Amount      `json:"amount"`
+	Shipping Address     `json:"shipping"`
+	Tracking Tracking    `json:"tracking"`
+}
+
+type Subscription struct {
+	ID               string `json:"id"`
+	ProductVariantID string `json:"productVariantID"`
+	Quantity         int64  `json:"quantity"`
+	Frequency        string `json:"frequency"`
+	AddressID        string `json:"addressID"`
+	CardID           string `json:"cardID"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+func Write(w io.Writer, kind string, data interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Document{
+		Version: Version,
+		Kind:    kind,
+		Data:    data,
+	})
+}
+
+func FromProducts(products []terminal.Product) []Product {
+	result := []Product{}
+	for _, product := range products {
+		variants := []Variant{}
+		for _, variant := range product.Variants {
+			variants = append(variants, Variant{
+				ID:    variant.ID,
+				Name:  variant.Name,
+				Price: variant.Price,
+			})
+		}
+		tags := map[string]string{}
+		for key, value := range product.Tags {
+			tags[key] = value
+		}
+		result = append(result, Product{
+			ID:           product.ID,
+			Name:         product.Name,
+			Description:  product.Description,
+			Subscription: string(product.Subscription),
+			Tags:         tags,
+			Variants:     variants,
+		})
+	}
+	return result
+}
+
+// FromCart converts the cart, discount comes from api.GetCartExtras.
+func FromCart(cart terminal.Cart, discount int64) Cart {
+	items := []CartItem{}
+	for _, item := range cart.Items {
+		if item.Quantity == 0 {
+			continue
+		}
+		items = append(items, CartItem{
+			ID:               item.ID,
+			ProductVariantID: item.ProductVariantID,
+			Quantity:         item.Quantity,
+			Subtotal:         item.Subtotal,
+		})
+	}
+	return Cart{
+		Items: items,
+		Amount: Amount{
+			Subtotal: cart.Amount.Subtotal,
+			Shipping: cart.Amount.Shipping,
+			Discount: discount,
+			Total:    cart.Amount.Subtotal + cart.Amount.Shipping - discount,
+		},
+		AddressID: cart.AddressID,
+		CardID:    cart.CardID,
+		Shipping: CartShipping{
+			Service:   cart.Shipping.Service,
+			Timeframe: cart.Shipping.Timeframe,
+		},
+	}
+}
+
+func FromAddresses(addresses []terminal.Address) []Address {
+	result := []Address{}
+	for _, address := range addresses {
+		result = append(result, Address{
+			ID:       address.ID,
+			Name:     address.Name,
+			Street1:  address.Street1,
+			Street2:  address.Street2,
+			City:     address.City,
+			Province: address.Province,
+			Country:  address.Country,
+			Zip:      address.Zip,
+			Phone:    address.Phone,
+		})
+	}
+	return result
+}
+
+func FromCards(cards []terminal.Card) []Card {
+	result := []Card{}
+	for _, card := range cards {
+		result = append(result, Card{
+			ID:    card.ID,
+			Brand: card.Brand,
+			Last4: card.Last4,
+			Expiration: fmt.Sprintf(
+				"%02d/%02d",
+				card.Expiration.Month,
+				card.Expiration.Year%100,
+			),
+		})
+	}
+	return result
+}
+
+// FromOrder converts the order, discount comes from api.GetOrderDiscount.
+func FromOrder(order terminal.Order, discount int64) Order {
+	items := []OrderItem{}
+	for _, item := range order.Items {
+		items = append(items, OrderItem{
+			ID:               item.ID,
+			ProductVariantID: item.ProductVariantID,
+			Description:      item.Description,
+			Quantity:         item.Quantity,
+			Amount:           item.Amount,
+		})
+	}
+	created := ""
+	if t, ok := api.GetOrderCreated(order.ID); ok {
+		created = t.Format(time.RFC3339)
+	}
+	return Order{
+		ID:      order.ID,
+		Index:   order.Index,
+		Created: created,
+		Items:   items,
+		Amount: Amount{
+			Subtotal: order.Amount.Subtotal,
+			Shipping: order.Amount.Shipping,
+			Discount: discount,
+			Total:    order.Amount.Subtotal + order.Amount.Shipping - discount,
+		},
+		Shipping: Address{
+			Name:     order.Shipping.Name,
+			Street1:  order.Shipping.Street1,
+			Street2:  order.Shipping.Street2,
+			City:     order.Shipping.City,
+			Province: order.Shipping.Province,
+			Country:  order.Shipping.Country,
+			Zip:      order.Shipping.Zip,
+			Phone:    order.Shipping.Phone,
+		},
+		Tracking: Tracking{
+			Service: order.Tracking.Service,
+			Number:  order.Tracking.Number,
+			URL:     order.Tracking.URL,
+		},
+	}
+}
+
+func FromOrders(orders []terminal.Order, discounts api.OrderDiscounts) []Order {
+	result := []Order{}
+	for _, order := range orders {
+		result = append(result, FromOrder(order, discounts[order.ID]))
+	}
+	return result
+}
+
+func FromSubscriptions(subscriptions []terminal.Subscription) []Subscription {
+	result := []Subscription{}
+	for _, subscription := range subscriptions {
+		result = append(result, Subscription{
+			ID:               subscription.ID,
+			ProductVariantID: subscription.ProductVariantID,
+			Quantity:         subscription.Quantity,
+			Frequency:        string(subscription.Frequency),
+			AddressID:        subscription.AddressID,
+			CardID:           subscription.CardID,
+		})
+	}
+	return result
+}
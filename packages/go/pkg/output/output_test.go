@@ -0,0 +1,51 @@
+package output_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/output"
+)
+
+// The documents are a public contract, so any change to the encoded field
+// names has to show up as a diff against testdata/order.json.
+func TestOrderSchema(t *testing.T) {
+	order := terminal.Order{
+		ID:    "ord_01J1KQX8Y0000000000000000",
+		Index: 2,
+		Items: []terminal.OrderItem{{
+			ID:               "itm_1",
+			ProductVariantID: "var_1",
+			Quantity:         2,
+			Amount:           4400,
+		}},
+		Amount: terminal.OrderAmount{Subtotal: 4400, Shipping: 800},
+		Shipping: terminal.OrderShipping{
+			Name:     "John Doe",
+			Street1:  "123 Main St",
+			City:     "Anytown",
+			Province: "CA",
+			Country:  "US",
+			Zip:      "12345",
+		},
+		Tracking: terminal.OrderTracking{
+			Service: "USPS Ground Advantage",
+			Number:  "92346903470167000000000019",
+		},
+	}
+
+	var got bytes.Buffer
+	if err := output.Write(&got, "order", output.FromOrder(order, 500)); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("testdata/order.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("order document changed:\n%s\nwant:\n%s", got.String(), want)
+	}
+}
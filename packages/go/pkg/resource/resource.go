@@ -1,10 +1,26 @@
+// Package resource holds the settings SST links to the go binaries: API and
+// auth URLs, keys and secrets.
+//
+// Nothing is read until a binary calls Load, so packages that import this one
+// can be used from tests and tools without an SST environment. Values come
+// from, in order of precedence:
+//
+//   - SST_RESOURCE_<Name> variables holding JSON, e.g. SST_RESOURCE_Api={"url":"..."}
+//   - a TOML or JSON config file, with a table or object per name
+//   - the defaults, which point at production
+//
+// Fields are referred to as Name.field, e.g. "SSHKey.private".
 package resource
 
 import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
 )
 
 type resource struct {
@@ -26,20 +42,121 @@ type resource struct {
 	}
 }
 
+// Resource is zero until Load succeeds.
 var Resource resource
 
-func init() {
-	val := reflect.ValueOf(&Resource).Elem()
+type Options struct {
+	// File is a config file, parsed as TOML or JSON depending on the
+	// extension. Empty means no file.
+	File string
+	// Required lists the fields the binary can't run without.
+	Required []string
+}
+
+// ValidationError lists every required field that's missing.
+type ValidationError struct {
+	Missing []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(
+		"missing configuration: %s (set SST_RESOURCE_<Name> or add them to the config file)",
+		strings.Join(e.Missing, ", "),
+	)
+}
+
+func defaults() resource {
+	result := resource{}
+	result.Api.Url = "https://api.terminal.shop"
+	result.Auth.Url = "https://auth.terminal.shop"
+	return result
+}
+
+// Load reads the configuration into Resource.
+func Load(options Options) error {
+	result := defaults()
+
+	if options.File != "" {
+		if err := loadFile(&result, options.File); err != nil {
+			return err
+		}
+	}
+
+	if err := loadEnv(&result); err != nil {
+		return err
+	}
+
+	missing := []string{}
+	for _, name := range options.Required {
+		value, ok := lookup(&result, name)
+		if !ok {
+			return fmt.Errorf("unknown configuration field %s", name)
+		}
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return &ValidationError{Missing: missing}
+	}
+
+	Resource = result
+	return nil
+}
+
+func loadEnv(result *resource) error {
+	val := reflect.ValueOf(result).Elem()
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
-		typeField := val.Type().Field(i)
-		envVarName := fmt.Sprintf("SST_RESOURCE_%s", typeField.Name)
+		envVarName := fmt.Sprintf("SST_RESOURCE_%s", val.Type().Field(i).Name)
 		envValue, exists := os.LookupEnv(envVarName)
 		if !exists {
-			panic(fmt.Sprintf("Environment variable %s is required", envVarName))
+			continue
 		}
 		if err := json.Unmarshal([]byte(envValue), field.Addr().Interface()); err != nil {
-			panic(err)
+			return fmt.Errorf("%s: %w", envVarName, err)
+		}
+	}
+	return nil
+}
+
+func loadFile(result *resource, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, result)
+	case ".toml":
+		// keys match the field names without regard to case, like the JSON
+		// tags
+		_, err = toml.Decode(string(data), result)
+	default:
+		return fmt.Errorf("%s: config file must be .toml or .json", path)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// lookup returns the value of a Name.field path.
+func lookup(result *resource, name string) (string, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	section := reflect.ValueOf(result).Elem().FieldByName(parts[0])
+	if !section.IsValid() {
+		return "", false
+	}
+	for i := 0; i < section.NumField(); i++ {
+		if section.Type().Field(i).Tag.Get("json") == parts[1] {
+			return section.Field(i).String(), true
 		}
 	}
+	return "", false
 }
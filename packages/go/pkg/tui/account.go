@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type accountState struct {
@@ -19,6 +20,13 @@ func (m model) AccountSwitch() (model, tea.Cmd) {
 	m.state.tokens = tokensState{
 		selected: 0,
 	}
+	m.state.keys = keysState{}
+	m.state.apps = appsState{}
+	m.state.subscriptions.form = nil
+	m.state.subscriptions.error = ""
+	m.state.shipping = shippingState{}
+	m.state.payment = paymentState{}
+	m.state.orders.detail = false
 	m.state.footer.commands = []footerCommand{
 		{key: "↑/↓", value: "navigate"},
 		{key: "enter", value: "select"},
@@ -27,12 +35,50 @@ func (m model) AccountSwitch() (model, tea.Cmd) {
 	return m, nil
 }
 
+// LoadDefaults loads the default address and card. Like the keys they're
+// newer than the rest, so the shop keeps working without them.
+func (m model) LoadDefaults() tea.Cmd {
+	return func() tea.Msg {
+		defaults, err := api.GetDefaults(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load defaults", "error", err)
+			return nil
+		}
+		return defaults
+	}
+}
+
+// accountFormOpen is true while a form on the account page has focus. Forms
+// take every key, including esc to close them and the header shortcuts.
+func (m model) accountFormOpen() bool {
+	if m.page != accountPage {
+		return false
+	}
+	if m.state.tokens.form != nil || m.state.keys.form != nil || m.state.apps.form != nil ||
+		m.state.subscriptions.form != nil {
+		return true
+	}
+	if !m.state.account.focused {
+		return false
+	}
+	switch m.accountPages[m.state.account.selected] {
+	case shippingPage:
+		return m.state.shipping.view == shippingFormView
+	case paymentPage:
+		return m.state.payment.view == paymentFormView
+	}
+	return false
+}
+
 func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 	accountPage := m.accountPages[m.state.account.selected]
 
 	if m.state.account.focused {
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
+		if accountPage == ordersPage && m.state.orders.detail {
+			return m.OrdersUpdate(msg)
+		}
+
+		if msg, ok := msg.(tea.KeyMsg); ok && !m.accountFormOpen() {
 			switch msg.String() {
 			case "esc", "left", "h":
 				s := m.state.account.selected
@@ -47,6 +93,10 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 			return m.SubscriptionsUpdate(msg)
 		case tokensPage:
 			return m.TokensUpdate(msg)
+		case keysPage:
+			return m.KeysUpdate(msg)
+		case appsPage:
+			return m.AppsUpdate(msg)
 		case ordersPage:
 			return m.OrdersUpdate(msg)
 		case shippingPage:
@@ -67,15 +117,31 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "enter", "right", "l":
 			if accountPage == subscriptionsPage ||
 				accountPage == ordersPage ||
-				accountPage == tokensPage {
+				accountPage == tokensPage ||
+				accountPage == keysPage ||
+				accountPage == appsPage ||
+				accountPage == shippingPage ||
+				accountPage == paymentPage {
 				m.state.account.focused = true
 				switch accountPage {
 				case subscriptionsPage:
 					return m.SubscriptionsUpdate(msg)
 				case tokensPage:
-					return m.TokensUpdate(msg)
+					// don't forward the key, enter would add a token
+					return m.TokensUpdate(nil)
+				case keysPage:
+					// don't forward the key, enter would pair a key
+					return m.KeysUpdate(nil)
+				case appsPage:
+					// don't forward the key, enter would add an app
+					return m.AppsUpdate(nil)
 				case ordersPage:
-					return m.OrdersUpdate(msg)
+					// don't forward the key, enter would open the order details
+					return m.OrdersUpdate(nil)
+				case shippingPage:
+					return m.AddressesManageSwitch()
+				case paymentPage:
+					return m.CardsManageSwitch()
 				}
 
 			}
@@ -94,6 +160,10 @@ func getAccountPageName(accountPage page) string {
 		return "Subscriptions"
 	case tokensPage:
 		return "Access Tokens"
+	case keysPage:
+		return "SSH Keys"
+	case appsPage:
+		return "Apps"
 	case shippingPage:
 		return "Addresses"
 	case paymentPage:
@@ -115,8 +185,14 @@ func (m model) GetAccountPageContent(accountPage page, totalWidth int) string {
 		return m.SubscriptionsView(totalWidth, m.state.account.focused)
 	case tokensPage:
 		return m.TokensView(totalWidth, m.state.account.focused)
+	case keysPage:
+		return m.KeysView(totalWidth, m.state.account.focused)
+	case appsPage:
+		return m.AppsView(totalWidth, m.state.account.focused)
 	case shippingPage:
 		return m.ShippingView(totalWidth, m.state.account.focused)
+	case paymentPage:
+		return m.PaymentView(totalWidth, m.state.account.focused)
 	case faqPage:
 		return m.FaqView()
 	case aboutPage:
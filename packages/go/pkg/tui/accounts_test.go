@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stripe/stripe-go/v78"
+)
+
+func TestAccountAddresses(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.press("a", "j", "j", "j", "j", "j", "enter")
+	h.expect("1 Analytical Way")
+	h.expect("edit selected address")
+
+	h.press(" ")
+	if id := h.model.(model).defaults.AddressID; id != "shp_test" {
+		t.Fatalf("expected shp_test to be the default address, got %q", id)
+	}
+	h.expect("default")
+
+	// "a" would open the account page if the header got the keys
+	h.press("enter", "enter")
+	for range "1 Analytical Way" {
+		h.press("backspace")
+	}
+	h.press("42 Oak Ave")
+	for range 7 {
+		h.press("enter")
+	}
+	h.expect("42 Oak Ave")
+
+	addresses := h.model.(model).addresses
+	if len(addresses) != 1 || addresses[0].ID != "shp_test" {
+		t.Fatalf("expected the address to be updated in place, got %+v", addresses)
+	}
+	h.expect("default")
+}
+
+func TestAccountCards(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.press("a", "j", "j", "j", "j", "j", "j", "enter")
+	h.expect("**** **** **** 4242")
+
+	h.press(" ")
+	h.expect("default")
+
+	h.press("x", "y")
+	m := h.model.(model)
+	if view := m.View(); strings.Contains(view, "4242") {
+		t.Errorf("expected the card to be deleted\n%s", view)
+	}
+	if m.defaults.CardID != "" {
+		t.Errorf("expected the default card to be cleared, got %q", m.defaults.CardID)
+	}
+}
+
+func TestAccountAddressesListFails(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.fake.Fail("GET /address")
+	h.press("a", "j", "j", "j", "j", "j", "enter")
+	h.press("enter", "enter")
+	h.press(" Suite 2")
+	for range 7 {
+		h.press("enter")
+	}
+	h.expect("Something went wrong")
+}
+
+func TestAccountCardsListFails(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.fake.Fail("GET /card")
+	h.press("a", "j", "j", "j", "j", "j", "j", "enter")
+	h.press("j", "enter")
+	h.send(&stripe.Token{ID: "tok_visa"})
+	h.expect("Something went wrong")
+}
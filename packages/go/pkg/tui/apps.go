@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+type AppAddedMsg struct {
+	newApp terminal.AppNewResponseData
+	app    terminal.App
+}
+
+type appsState struct {
+	selected int
+	deleting *int
+	newApp   *terminal.AppNewResponseData
+	form     *huh.Form
+	creating bool
+	error    string
+}
+
+func (m model) nextApp() (model, tea.Cmd) {
+	next := m.state.apps.selected + 1
+	max := len(m.apps)
+	if next > max {
+		next = max
+	}
+
+	m.state.apps.selected = next
+	return m, nil
+}
+
+func (m model) previousApp() (model, tea.Cmd) {
+	next := m.state.apps.selected - 1
+	if next < 0 {
+		next = 0
+	}
+
+	m.state.apps.selected = next
+	return m, nil
+}
+
+// isNewAppSelected is true while the app that was just created, whose secret
+// is still shown, is selected.
+func (m model) isNewAppSelected() bool {
+	return m.state.apps.newApp != nil &&
+		m.state.apps.selected < len(m.apps) &&
+		m.apps[m.state.apps.selected].ID == m.state.apps.newApp.ID
+}
+
+func (m model) appsFormStart() (model, tea.Cmd) {
+	m.state.apps.error = ""
+	m.state.apps.newApp = nil
+	m.state.apps.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("name").
+				Key("name").
+				Validate(validate.NotEmpty("name")),
+			huh.NewInput().
+				Title("redirect uri").
+				Key("redirectURI").
+				Placeholder("https://example.com/callback").
+				Validate(validate.Compose(
+					validate.NotEmpty("redirect uri"),
+					validate.URLValidator,
+				)),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+
+	return m, m.state.apps.form.Init()
+}
+
+func (m model) appsFormUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && !m.state.apps.creating {
+		m.state.apps.form = nil
+		return m, nil
+	}
+
+	next, cmd := m.state.apps.form.Update(msg)
+	m.state.apps.form = next.(*huh.Form)
+	if m.state.apps.creating || m.state.apps.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.state.apps.creating = true
+	params := terminal.AppNewParams{
+		Name:        terminal.F(strings.TrimSpace(m.state.apps.form.GetString("name"))),
+		RedirectURI: terminal.F(strings.TrimSpace(m.state.apps.form.GetString("redirectURI"))),
+	}
+	return m, func() tea.Msg {
+		response, err := m.client.App.New(m.context, params)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		// the response has everything the list shows, so don't refetch and
+		// risk losing the secret to a failed list
+		return AppAddedMsg{
+			newApp: response.Data,
+			app: terminal.App{
+				ID:          response.Data.ID,
+				Name:        params.Name.Value,
+				RedirectURI: params.RedirectURI.Value,
+			},
+		}
+	}
+}
+
+func (m model) AppsUpdate(msg tea.Msg) (model, tea.Cmd) {
+	m.state.footer.commands = []footerCommand{
+		{key: "↑/↓", value: "navigate"},
+		{key: "x/del", value: "delete"},
+		{key: "esc", value: "back"},
+	}
+	if m.isNewAppSelected() {
+		m.state.footer.commands = append(
+			[]footerCommand{{key: "y", value: "copy secret"}},
+			m.state.footer.commands...,
+		)
+	}
+
+	switch msg := msg.(type) {
+	case AppAddedMsg:
+		m.state.apps.form = nil
+		m.state.apps.creating = false
+		m.state.apps.newApp = &msg.newApp
+		m.apps = append(m.apps, msg.app)
+		return m, m.Copy("client secret", msg.newApp.Secret)
+	case VisibleError:
+		m.state.apps.form = nil
+		m.state.apps.creating = false
+		m.state.apps.error = msg.message
+		return m, nil
+	}
+
+	if m.state.apps.form != nil {
+		return m.appsFormUpdate(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down", "tab":
+			if m.state.apps.deleting == nil {
+				return m.nextApp()
+			}
+		case "k", "up", "shift+tab":
+			if m.state.apps.deleting == nil {
+				return m.previousApp()
+			}
+		case "delete", "d", "backspace", "x":
+			if m.state.apps.deleting == nil && m.state.apps.selected < len(m.apps) {
+				m.state.apps.deleting = &m.state.apps.selected
+			}
+			return m, nil
+		case "y":
+			if m.state.apps.deleting != nil {
+				m.state.apps.deleting = nil
+				id := m.apps[m.state.apps.selected].ID
+				return m, func() tea.Msg {
+					if _, err := m.client.App.Delete(m.context, id); err != nil {
+						return VisibleError{message: api.GetErrorMessage(err)}
+					}
+					apps, err := m.client.App.List(m.context)
+					if err != nil {
+						return VisibleError{message: api.GetErrorMessage(err)}
+					}
+					return apps.Data
+				}
+			}
+			if m.isNewAppSelected() {
+				return m, m.Copy("client secret", m.state.apps.newApp.Secret)
+			}
+			return m, nil
+		case "n", "esc":
+			m.state.apps.deleting = nil
+			return m, nil
+		case "enter":
+			if m.state.apps.deleting == nil && m.state.apps.selected == len(m.apps) {
+				return m.appsFormStart()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) formatApp(app terminal.App, totalWidth int) string {
+	lines := []string{}
+	lines = append(lines, m.theme.TextAccent().Render(app.Name))
+	lines = append(lines, "client id: "+app.ID)
+	lines = append(lines, "redirect: "+app.RedirectURI)
+
+	if m.state.apps.newApp != nil && app.ID == m.state.apps.newApp.ID {
+		lines = append(
+			lines,
+			"secret: "+m.theme.TextHighlight().Bold(true).Render(m.state.apps.newApp.Secret),
+		)
+		lines = append(lines, "(will not be shown again)")
+	} else if app.Secret != "" {
+		lines = append(lines, "secret: "+app.Secret)
+	}
+
+	return m.theme.Base().Width(totalWidth - 2).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
+
+func (m model) AppsView(totalWidth int, focused bool) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	apps := []string{}
+	if m.state.apps.error != "" {
+		apps = append(apps, m.theme.TextError().Width(totalWidth).Render(m.state.apps.error))
+	}
+	for i, app := range m.apps {
+		content := m.formatApp(app, totalWidth)
+		if m.state.apps.deleting != nil && *m.state.apps.deleting == i {
+			content = accent("are you sure you want to delete?") + base("\n(y/n)")
+		}
+		apps = append(apps, m.CreateBoxCustom(
+			content,
+			focused && i == m.state.apps.selected,
+			totalWidth,
+		))
+	}
+
+	newApp := "add oauth app"
+	if m.state.apps.creating {
+		newApp = "creating..."
+	} else if m.state.apps.form != nil {
+		newApp = m.state.apps.form.WithWidth(totalWidth - 4).View()
+	}
+	apps = append(apps, m.CreateCenteredBoxCustom(
+		newApp,
+		focused && m.state.apps.selected == len(m.apps),
+		totalWidth,
+	))
+
+	return m.theme.Base().Render(lipgloss.JoinVertical(lipgloss.Left, apps...))
+}
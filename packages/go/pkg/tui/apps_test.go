@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApps(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	clipboard := captureClipboard(h)
+	h.press("a", "j", "j", "j", "j", "enter")
+	h.expect("add oauth app")
+
+	h.press("enter")
+	for _, char := range "ci" {
+		h.press(string(char))
+	}
+	h.press("enter")
+	h.press("not a url", "enter")
+	h.expect("not a valid url")
+
+	for range "not a url" {
+		h.press("backspace")
+	}
+	h.press("https://ci.example.com/callback", "enter")
+
+	app := h.model.(model).state.apps.newApp
+	if app == nil {
+		t.Fatal("expected a new app")
+	}
+	h.expect("redirect: https://ci.example.com/callback")
+	h.expect(app.Secret)
+	h.expect("(will not be shown again)")
+	expectCopied(t, clipboard, app.Secret)
+
+	h.press("x")
+	h.expect("are you sure you want to delete?")
+	h.press("n")
+	h.expect("redirect: https://ci.example.com/callback")
+
+	h.press("x", "y")
+	if view := h.model.View(); strings.Contains(view, "redirect:") {
+		t.Errorf("expected the app to be deleted\n%s", view)
+	}
+}
+
+func TestAppsListFails(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	captureClipboard(h)
+	h.fake.Fail("GET /app")
+	h.press("a", "j", "j", "j", "j", "enter")
+	h.press("enter")
+	for _, char := range "ci" {
+		h.press(string(char))
+	}
+	h.press("enter")
+	h.press("https://ci.example.com/callback", "enter")
+
+	app := h.model.(model).state.apps.newApp
+	if app == nil {
+		t.Fatal("expected a new app")
+	}
+	h.expect("redirect: https://ci.example.com/callback")
+	h.expect(app.Secret)
+}
@@ -18,9 +18,9 @@ func cartBreadcrumbsView(m model) string {
 	case small:
 		fallthrough
 	case medium:
-		labels = []string{"cart", "ship", "pay", "confirm"}
+		labels = []string{"cart", "ship", "deliver", "pay", "confirm"}
 	default:
-		labels = []string{"cart", "shipping", "payment", "confirmation"}
+		labels = []string{"cart", "shipping", "delivery", "payment", "confirmation"}
 	}
 
 	var selected int
@@ -29,10 +29,12 @@ func cartBreadcrumbsView(m model) string {
 		selected = 0
 	case shippingPage:
 		selected = 1
-	case paymentPage:
+	case deliveryPage:
 		selected = 2
-	case confirmPage:
+	case paymentPage:
 		selected = 3
+	case confirmPage:
+		selected = 4
 	default:
 		return ""
 	}
@@ -70,7 +72,12 @@ func subscribeBreadcrumbsView(m model) string {
 	case medium:
 		labels = []string{"subscribe", "ship", "pay", "confirm"}
 	default:
-		labels = []string{"subscribe", "shipping", "payment", "confirmation"}
+		labels = []string{
+			"subscribe " + formatFrequency(m.state.subscribe.frequency),
+			"shipping",
+			"payment",
+			"confirmation",
+		}
 	}
 
 	var selected int
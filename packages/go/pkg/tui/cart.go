@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type cartState struct {
@@ -21,6 +22,18 @@ type CartUpdatedMsg struct {
 	updated  terminal.Cart
 }
 
+// LoadCartExtras loads the cart's promo and the free shipping threshold.
+func (m model) LoadCartExtras() tea.Cmd {
+	return func() tea.Msg {
+		extras, err := api.GetCartExtras(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load cart extras", "error", err)
+			return nil
+		}
+		return extras
+	}
+}
+
 func (m model) IsCartEmpty() bool {
 	return m.CartItemCount() == 0
 }
@@ -59,32 +72,39 @@ func (m model) GetCartItem(productVariantID string) (terminal.CartItem, int) {
 }
 
 func (m model) GetProduct(cartItem terminal.CartItem) (*terminal.Product, int) {
-	index := -1
-	for i, product := range m.products {
-		if product.Variants[0].ID == cartItem.ProductVariantID {
-			index = i
-			break
+	product, _ := m.GetProductVariant(cartItem.ProductVariantID)
+	if product == nil {
+		return nil, -1
+	}
+
+	for i := range m.products {
+		if m.products[i].ID == product.ID {
+			return product, i
 		}
 	}
 
-	var product *terminal.Product
-	if index == -1 {
-		return nil, index
-	} else {
-		product = &m.products[index]
+	return nil, -1
+}
+
+func (m model) GetProductVariant(productVariantID string) (*terminal.Product, *terminal.ProductVariant) {
+	for i := range m.products {
+		product := &m.products[i]
+		for j := range product.Variants {
+			if product.Variants[j].ID == productVariantID {
+				return product, &product.Variants[j]
+			}
+		}
 	}
 
-	return product, index
+	return nil, nil
 }
 
 func (m model) CalculateSubtotal() int64 {
 	subtotal := int64(0)
 	for _, item := range m.cart.Items {
-		for _, product := range m.products {
-			variant := product.Variants[0]
-			if variant.ID == item.ProductVariantID {
-				subtotal += item.Quantity * variant.Price
-			}
+		_, variant := m.GetProductVariant(item.ProductVariantID)
+		if variant != nil {
+			subtotal += item.Quantity * variant.Price
 		}
 	}
 	return subtotal
@@ -92,7 +112,10 @@ func (m model) CalculateSubtotal() int64 {
 
 func (m model) UpdateCart(productVariantID string, offset int64) (model, tea.Cmd) {
 	cartItem, index := m.GetCartItem(productVariantID)
-	product, _ := m.GetProduct(cartItem)
+	_, variant := m.GetProductVariant(productVariantID)
+	if variant == nil {
+		return m, nil
+	}
 
 	next := cartItem.Quantity + offset
 	if next < 0 {
@@ -100,11 +123,11 @@ func (m model) UpdateCart(productVariantID string, offset int64) (model, tea.Cmd
 	}
 	if index == -1 {
 		cartItem.Quantity = next
-		cartItem.Subtotal = product.Variants[0].Price * next
+		cartItem.Subtotal = variant.Price * next
 		m.cart.Items = append(m.cart.Items, cartItem)
 	} else {
 		m.cart.Items[index].Quantity = next
-		m.cart.Items[index].Subtotal = product.Variants[0].Price * next
+		m.cart.Items[index].Subtotal = variant.Price * next
 	}
 
 	updateID := time.Now().UTC().UnixMilli()
@@ -214,9 +237,12 @@ func (m model) CartView() string {
 
 	var lines []string
 	for i, item := range m.VisibleCartItems() {
-		product, _ := m.GetProduct(item)
+		product, variant := m.GetProductVariant(item.ProductVariantID)
+		if product == nil {
+			continue
+		}
 		name := accent(product.Name)
-		description := base(strings.ToLower(product.Variants[0].Name))
+		description := base(strings.ToLower(variant.Name))
 		quantity := base("  ") + accent(strconv.FormatInt(item.Quantity, 10)) + base("    ")
 		if m.state.cart.selected == i {
 			quantity = base("- ") + accent(strconv.FormatInt(item.Quantity, 10)) + base(" +  ")
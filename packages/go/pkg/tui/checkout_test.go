@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestCheckout(t *testing.T) {
+	for _, size := range testSizes {
+		t.Run(size.name, func(t *testing.T) {
+			h := newHarness(t, size.width, size.height)
+			golden := func(step string) {
+				t.Helper()
+				h.golden(filepath.Join("checkout", size.name, step))
+			}
+
+			golden("01-shop")
+			h.press("v", "+", "+")
+			golden("02-shop-added")
+			h.press("enter")
+			golden("03-cart")
+			h.press("enter")
+			golden("04-shipping")
+			h.press("enter")
+			golden("05-delivery")
+			h.press("enter")
+			golden("06-payment")
+			h.press("enter")
+			golden("07-confirm")
+			h.press("enter")
+			golden("08-final")
+			h.press("enter")
+			if !h.quit {
+				t.Error("expected the program to quit after the final page")
+			}
+		})
+	}
+}
+
+func TestCheckoutPromo(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.press("+", "enter", "enter", "enter", "enter", "enter")
+	h.expect("Total:    $30.00")
+
+	h.press("p", "N", "O", "P", "E", "enter")
+	h.expect("Invalid promo code")
+	h.expect("press enter to confirm")
+
+	h.press("p", "c", "o", "n", "f", "2", "0", "2", "6", "enter")
+	h.expect("Discount: -$4.40 (CONF2026)")
+	h.expect("Total:    $25.60")
+
+	// esc leaves the applied code alone
+	h.press("p", "esc")
+	h.expect("Discount: -$4.40 (CONF2026)")
+
+	h.press("esc")
+	h.expect("Discount: -$4.40")
+	h.press("enter")
+
+	h.press("enter")
+	h.expect("Thank you for ordering")
+	m := h.model.(model)
+	extras, err := api.GetCartExtras(m.context, m.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extras.Promo != nil || m.promo != nil {
+		t.Errorf("expected the promo to be used up by the order, got %+v", extras.Promo)
+	}
+}
+
+func TestOrderDiscount(t *testing.T) {
+	h := newHarness(t, 100, 40)
+
+	order := terminal.Order{ID: "ord_01JA5Y8ZPQ3M1D2S3TEXAMPLE0"}
+	order.Amount.Subtotal = 2200
+	order.Amount.Shipping = 800
+	h.send([]terminal.Order{order})
+	h.send(api.OrderDiscounts{order.ID: 440})
+
+	h.press("a", "enter")
+	h.expect("$25")
+	h.press("enter")
+	h.expect("-$4.40")
+	h.expect("$25.60")
+}
+
+func TestCheckoutDelivery(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.press("+", "enter", "enter", "enter")
+	h.expect("cart / shipping / delivery / payment / confirmation")
+	h.expect("USPS Ground Advantage")
+	h.expect("arrives in 1-2 days")
+
+	h.press("j", "enter")
+	h.expect("Shipping: $12.00")
+	h.press("enter")
+	h.expect("USPS Priority Mail")
+	h.expect("1-3 days")
+	h.expect("Total:    $34.00")
+
+	// going back keeps the chosen method selected
+	h.press("esc", "esc")
+	if selected := h.model.(model).state.delivery.selected; selected != 1 {
+		t.Errorf("expected USPS Priority Mail to stay selected, got %d", selected)
+	}
+}
+
+func TestFreeShippingProgress(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.expect("free shipping on US orders over $40.00")
+
+	h.press("+")
+	h.expect("$18.00 more for free shipping on US orders")
+	h.press("+")
+	h.expect("free shipping unlocked on US orders")
+
+	// free shipping doesn't apply outside the threshold's country
+	m := h.model.(model)
+	m.addresses = append(m.addresses, terminal.Address{ID: "shp_intl", Country: "CA"})
+	m.cart.AddressID = "shp_intl"
+	if view := m.freeShippingView(); view != "" {
+		t.Errorf("expected no free shipping progress for CA, got %q", view)
+	}
+	m.cart.AddressID = "shp_test"
+	if view := m.freeShippingView(); view != "free shipping unlocked" {
+		t.Errorf("expected free shipping to be unlocked, got %q", view)
+	}
+}
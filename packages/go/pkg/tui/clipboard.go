@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyMsg carries text to put on the clipboard to Update, which writes it
+// from the program's goroutine rather than a command's.
+type copyMsg struct {
+	what string
+	text string
+}
+
+type toastState struct {
+	message string
+	// id tells the clear message for this toast apart from older ones
+	id int
+}
+
+type toastClearMsg struct {
+	id int
+}
+
+const toastDuration = 2 * time.Second
+
+// Copy puts text on the clipboard with an OSC52 escape sequence. The terminal
+// on the other end of the session does the copying, so it works over SSH.
+// Terminals without OSC52 ignore the sequence, there's no way to tell.
+func (m model) Copy(what string, text string) tea.Cmd {
+	if m.output == nil || text == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		return copyMsg{what: what, text: text}
+	}
+}
+
+func (m model) ToastUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case copyMsg:
+		m.output.Copy(msg.text)
+		m.state.toast.id++
+		m.state.toast.message = msg.what + " copied to clipboard"
+		id := m.state.toast.id
+		return m, tea.Tick(toastDuration, func(t time.Time) tea.Msg {
+			return toastClearMsg{id: id}
+		})
+	case toastClearMsg:
+		if msg.id == m.state.toast.id {
+			m.state.toast.message = ""
+		}
+	}
+	return m, nil
+}
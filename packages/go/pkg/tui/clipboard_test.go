@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// captureClipboard sends the model's output to a buffer, to see what it
+// copies.
+func captureClipboard(h *harness) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	m := h.model.(model)
+	m.output = termenv.NewOutput(buffer)
+	h.model = m
+	return buffer
+}
+
+func expectCopied(t *testing.T, buffer *bytes.Buffer, text string) {
+	t.Helper()
+	osc52 := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text))
+	if !strings.Contains(buffer.String(), osc52) {
+		t.Errorf("expected %q to be copied, got %q", text, buffer.String())
+	}
+	buffer.Reset()
+}
+
+func TestCopyNewToken(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	clipboard := captureClipboard(h)
+	h.press("a", "j", "j", "enter")
+	h.press("enter", "enter", "enter", "enter")
+
+	token := h.model.(model).state.tokens.newToken
+	if token == nil {
+		t.Fatal("expected a new token")
+	}
+	expectCopied(t, clipboard, token.Token)
+	h.expect("token copied to clipboard")
+
+	h.send(toastClearMsg{id: h.model.(model).state.toast.id})
+	if view := h.model.View(); strings.Contains(view, "copied") {
+		t.Errorf("expected the toast to clear\n%s", view)
+	}
+
+	h.press("y")
+	expectCopied(t, clipboard, token.Token)
+}
+
+func TestCopyOrder(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	clipboard := captureClipboard(h)
+
+	order := terminal.Order{ID: "ord_01JA5Y8ZPQ3M1D2S3TEXAMPLE0"}
+	order.Tracking.Service = "USPS Ground Advantage"
+	order.Tracking.Number = "9400111899223847561234"
+	h.send([]terminal.Order{order})
+
+	h.press("a", "enter", "enter")
+	h.press("y")
+	expectCopied(t, clipboard, order.ID)
+	h.expect("order ID copied to clipboard")
+
+	h.press("t")
+	expectCopied(t, clipboard, order.Tracking.Number)
+	h.expect("tracking number copied to clipboard")
+}
@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/huh"
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
@@ -13,31 +13,128 @@ import (
 type confirmState struct {
 	submitting bool
 	error      string
+	promo      *huh.Form
+	applying   bool
+}
+
+// CartPromoMsg carries the promo applied to the cart, nil once it's removed.
+type CartPromoMsg struct {
+	promo *api.Promo
+}
+
+// CartDiscount is what the applied promo takes off the order. Promos don't
+// apply to subscriptions.
+func (m model) CartDiscount() int64 {
+	if m.promo == nil || m.IsSubscribing() {
+		return 0
+	}
+	return m.promo.Discount(m.cart.Amount.Subtotal)
 }
 
 func (m model) ConfirmSwitch() (model, tea.Cmd) {
 	m = m.SwitchPage(confirmPage)
 	m.state.confirm.error = ""
 	m.state.confirm.submitting = false
+	m.state.confirm.promo = nil
+	m.state.confirm.applying = false
 	m.state.footer.commands = []footerCommand{
 		{key: "esc", value: "back"},
 		{key: "enter", value: "next"},
 	}
+	if !m.IsSubscribing() {
+		m.state.footer.commands = append(
+			[]footerCommand{{key: "p", value: "promo code"}},
+			m.state.footer.commands...,
+		)
+	}
 	return m, nil
 }
 
+func (m model) confirmPromoStart() (model, tea.Cmd) {
+	m.state.confirm.error = ""
+	code := ""
+	if m.promo != nil {
+		code = m.promo.Code
+	}
+	m.state.confirm.promo = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("promo code").
+				Key("code").
+				Placeholder("leave empty to remove").
+				Value(&code),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+
+	return m, m.state.confirm.promo.Init()
+}
+
+func (m model) confirmPromoUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && !m.state.confirm.applying {
+		m.state.confirm.promo = nil
+		return m, nil
+	}
+
+	next, cmd := m.state.confirm.promo.Update(msg)
+	m.state.confirm.promo = next.(*huh.Form)
+	if m.state.confirm.applying || m.state.confirm.promo.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.state.confirm.applying = true
+	code := strings.TrimSpace(m.state.confirm.promo.GetString("code"))
+	return m, func() tea.Msg {
+		if code == "" {
+			if err := api.RemoveCartPromo(m.context, m.client); err != nil {
+				return VisibleError{message: api.GetErrorMessage(err)}
+			}
+			return CartPromoMsg{}
+		}
+		promo, err := api.SetCartPromo(m.context, m.client, code)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return CartPromoMsg{promo: promo}
+	}
+}
+
 func (m model) ConfirmUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case CartPromoMsg:
+		m.state.confirm.promo = nil
+		m.state.confirm.applying = false
+		return m, nil
+	case VisibleError:
+		if m.state.confirm.applying {
+			m.state.confirm.promo = nil
+			m.state.confirm.applying = false
+			m.state.confirm.error = msg.message
+			return m, nil
+		}
+	}
+
+	if m.state.confirm.promo != nil {
+		return m.confirmPromoUpdate(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "p":
+			if !m.IsSubscribing() && !m.state.confirm.submitting {
+				return m.confirmPromoStart()
+			}
 		case "esc":
 			return m.PaymentSwitch()
 		case "enter":
+			if m.state.confirm.submitting {
+				return m, nil
+			}
 			m.state.confirm.submitting = true
 			return m, func() tea.Msg {
 				if m.IsSubscribing() {
-					m.subscription.Quantity = terminal.Int(1)
-					m.subscription.Frequency = terminal.F(terminal.SubscriptionFrequencyFixed)
 					params := terminal.SubscriptionNewParams{Subscription: m.subscription}
 					subscription, err := m.client.Subscription.New(m.context, params)
 					if err != nil {
@@ -61,9 +158,11 @@ func (m model) ConfirmUpdate(msg tea.Msg) (model, tea.Cmd) {
 	case VisibleError:
 		m.state.confirm.submitting = false
 		m.state.payment.error = msg.message
-		log.Error(msg.message)
+		m.logger.Error("checkout failed", "error", msg.message)
 		return m.ShippingSwitch()
 	case terminal.Order:
+		// the API clears the promo once it's used
+		m.promo = nil
 		return m.FinalSwitch()
 	case *terminal.SubscriptionNewResponse:
 		return m.FinalSwitch()
@@ -86,7 +185,11 @@ func (m model) ConfirmView() string {
 			m.theme.TextAccent().
 				Render(m.state.subscribe.product.Name + ": " + m.state.subscribe.product.Variants[m.state.subscribe.selected].Name),
 		)
-		view.WriteString("\nMonthly Subscription\n")
+		view.WriteString(fmt.Sprintf(
+			"\nQuantity: %d, ships %s\n",
+			m.state.subscribe.quantity,
+			formatFrequency(m.state.subscribe.frequency),
+		))
 		view.WriteString("\n")
 	}
 	view.WriteString(address.Name + "\n")
@@ -109,22 +212,33 @@ func (m model) ConfirmView() string {
 	var subtotal int
 	var shipping int
 	if m.IsSubscribing() {
-		subtotal = int(m.state.subscribe.product.Variants[m.state.subscribe.selected].Price)
+		subtotal = int(m.SubscribePrice())
 		shipping = 0
 	} else {
 		subtotal = int(m.cart.Amount.Subtotal)
 		shipping = int(m.cart.Amount.Shipping)
 	}
-	total := subtotal + shipping
+	discount := int(m.CartDiscount())
+	total := subtotal + shipping - discount
 
 	view.WriteString(fmt.Sprintf("Subtotal: %s", formatUSD(subtotal)) + "\n")
+	if m.promo != nil && !m.IsSubscribing() {
+		view.WriteString(fmt.Sprintf("Discount: -%s (%s)", formatUSD(discount), m.promo.Code) + "\n")
+	}
 	view.WriteString(fmt.Sprintf("Shipping: %s", formatUSD(shipping)) + "\n")
 	view.WriteString(
 		m.theme.TextAccent().
 			Render(fmt.Sprintf("Total:    %s", formatUSD(total)) + "\n"),
 	)
 	view.WriteString("\n")
-	view.WriteString(m.theme.TextHighlight().Render("press enter to confirm") + "\n")
+	switch {
+	case m.state.confirm.applying:
+		view.WriteString("applying promo code...\n")
+	case m.state.confirm.promo != nil:
+		view.WriteString(m.state.confirm.promo.View() + "\n")
+	default:
+		view.WriteString(m.theme.TextHighlight().Render("press enter to confirm") + "\n")
+	}
 	view.WriteString("\n")
 	view.WriteString(m.theme.TextError().Render(m.state.confirm.error))
 
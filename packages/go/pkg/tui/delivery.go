@@ -0,0 +1,162 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+type ShippingRatesLoadedMsg struct {
+	rates []api.ShippingRate
+}
+
+type SelectedDeliveryUpdatedMsg struct {
+	cart terminal.Cart
+}
+
+type deliveryState struct {
+	rates      []api.ShippingRate
+	selected   int
+	loading    bool
+	submitting bool
+	error      string
+}
+
+// DeliverySwitch shows the shipping methods for the cart's address. Orders
+// only, subscriptions always ship with the cheapest method.
+func (m model) DeliverySwitch() (model, tea.Cmd) {
+	m = m.SwitchPage(deliveryPage)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "back"},
+		{key: "↑/↓", value: "methods"},
+		{key: "enter", value: "select"},
+	}
+	m.state.delivery = deliveryState{loading: true}
+
+	return m, func() tea.Msg {
+		rates, err := api.ListShippingRates(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load shipping rates", "error", err)
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return ShippingRatesLoadedMsg{rates: rates}
+	}
+}
+
+func (m model) chooseDeliveryMethod() (model, tea.Cmd) {
+	if m.state.delivery.selected >= len(m.state.delivery.rates) {
+		return m, nil
+	}
+	rate := m.state.delivery.rates[m.state.delivery.selected]
+	m.state.delivery.submitting = true
+	m.state.delivery.error = ""
+
+	return m, func() tea.Msg {
+		if err := api.SetShippingRate(m.context, m.client, rate.ID); err != nil {
+			m.logger.Error("could not set shipping method", "error", err)
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		cart, err := m.client.Cart.Get(m.context)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return SelectedDeliveryUpdatedMsg{cart: cart.Data}
+	}
+}
+
+func (m model) DeliveryUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ShippingRatesLoadedMsg:
+		m.state.delivery.loading = false
+		m.state.delivery.rates = msg.rates
+		for i, rate := range msg.rates {
+			if rate.Service == m.cart.Shipping.Service {
+				m.state.delivery.selected = i
+			}
+		}
+		return m, nil
+	case SelectedDeliveryUpdatedMsg:
+		m.state.delivery.submitting = false
+		m.cart = msg.cart
+		return m.PaymentSwitch()
+	case VisibleError:
+		m.state.delivery.loading = false
+		m.state.delivery.submitting = false
+		m.state.delivery.error = msg.message
+		return m, nil
+	case tea.KeyMsg:
+		if m.state.delivery.submitting {
+			return m, nil
+		}
+		switch msg.String() {
+		case "j", "down", "tab":
+			if m.state.delivery.selected < len(m.state.delivery.rates)-1 {
+				m.state.delivery.selected++
+			}
+		case "k", "up", "shift+tab":
+			if m.state.delivery.selected > 0 {
+				m.state.delivery.selected--
+			}
+		case "enter":
+			return m.chooseDeliveryMethod()
+		case "esc":
+			return m.ShippingSwitch()
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) formatShippingRate(rate api.ShippingRate, totalWidth int) string {
+	price := formatUSD(int(rate.Amount))
+	if rate.Amount == 0 {
+		price = "free"
+	}
+	space := totalWidth - lipgloss.Width(rate.Service) - lipgloss.Width(price) - 2
+
+	lines := []string{}
+	lines = append(lines, lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		m.theme.TextAccent().Render(rate.Service),
+		m.theme.Base().Width(space).Render(),
+		m.theme.TextHighlight().Render(price),
+	))
+	if rate.Timeframe != "" {
+		lines = append(lines, "arrives in "+rate.Timeframe)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m model) DeliveryView(totalWidth int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	switch {
+	case m.state.delivery.loading:
+		return m.theme.Base().Width(totalWidth).Render("loading shipping methods...")
+	case m.state.delivery.submitting:
+		return m.theme.Base().Width(totalWidth).Render("calculating shipping costs...")
+	}
+
+	rates := []string{}
+	for i, rate := range m.state.delivery.rates {
+		rates = append(rates, m.CreateBoxCustom(
+			m.formatShippingRate(rate, totalWidth),
+			i == m.state.delivery.selected,
+			totalWidth,
+		))
+	}
+
+	lines := []string{}
+	if m.state.delivery.error != "" {
+		lines = append(lines, m.theme.TextError().Render(m.state.delivery.error))
+	}
+	lines = append(lines, rates...)
+	if len(rates) > 0 {
+		lines = append(lines, accent("enter ")+base("use selected method"))
+	}
+
+	return m.theme.Base().Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
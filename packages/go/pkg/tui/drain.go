@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DrainMsg tells the model the server is shutting down. Sessions about to
+// place an order get to finish it, everyone else is disconnected after
+// drainNotice.
+type DrainMsg struct{}
+
+type drainState struct {
+	draining  bool
+	scheduled bool
+}
+
+type drainQuitMsg struct{}
+
+const drainNotice = 5 * time.Second
+
+func (m model) DrainUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg.(type) {
+	case DrainMsg:
+		m.state.drain.draining = true
+		// the banner takes a line from the viewport
+		m.switched = true
+	case drainQuitMsg:
+		m.state.drain.scheduled = false
+	}
+
+	if !m.state.drain.draining || m.state.drain.scheduled {
+		return m, nil
+	}
+
+	// keep the session alive while the order is being confirmed, the quit
+	// is scheduled again once it's placed or abandoned
+	if m.page == confirmPage {
+		return m, nil
+	}
+
+	if _, ok := msg.(drainQuitMsg); ok {
+		return m, tea.Quit
+	}
+
+	m.state.drain.scheduled = true
+	return m, tea.Tick(drainNotice, func(t time.Time) tea.Msg {
+		return drainQuitMsg{}
+	})
+}
+
+func (m model) DrainView() string {
+	message := "server restarting, reconnect in a minute"
+	if m.page == confirmPage {
+		message = "server restarting, place your order now"
+	}
+
+	return m.theme.TextError().
+		Width(m.widthContainer).
+		Align(lipgloss.Center).
+		Render(message)
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"testing"
+)
+
+func TestDrain(t *testing.T) {
+	h := newHarness(t, 100, 30)
+	h.send(DrainMsg{})
+	h.golden("drain/shop")
+	h.send(drainQuitMsg{})
+	if !h.quit {
+		t.Error("expected the shop to close after the notice")
+	}
+}
+
+func TestDrainConfirm(t *testing.T) {
+	h := newHarness(t, 100, 30)
+	h.press("+", "enter", "enter", "enter", "enter", "enter")
+	h.send(DrainMsg{})
+	h.golden("drain/confirm")
+
+	h.send(drainQuitMsg{})
+	if h.quit {
+		t.Fatal("expected the checkout to be allowed to finish")
+	}
+
+	h.press("enter")
+	h.send(drainQuitMsg{})
+	if !h.quit {
+		t.Error("expected the session to close once the order was placed")
+	}
+}
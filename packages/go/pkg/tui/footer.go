@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -13,6 +15,37 @@ type footerCommand struct {
 	value string
 }
 
+// freeShippingView shows how far the cart is from free shipping. It's empty
+// until the threshold loads, and when the cart ships somewhere it doesn't
+// apply.
+func (m model) freeShippingView() string {
+	if m.freeShipping == nil || m.freeShipping.Threshold <= 0 {
+		return ""
+	}
+	threshold := m.freeShipping.Threshold
+	subtotal := m.cart.Subtotal
+
+	// the country only needs saying until there's an address
+	where := " on " + m.freeShipping.Country + " orders"
+	for _, address := range m.addresses {
+		if address.ID != m.cart.AddressID {
+			continue
+		}
+		if address.Country != m.freeShipping.Country {
+			return ""
+		}
+		where = ""
+	}
+
+	switch {
+	case subtotal == 0:
+		return fmt.Sprintf("free shipping%s over %s", where, formatUSD(int(threshold)))
+	case subtotal < threshold:
+		return fmt.Sprintf("%s more for free shipping%s", formatUSD(int(threshold-subtotal)), where)
+	}
+	return "free shipping unlocked" + where
+}
+
 func (m model) FooterView() string {
 	bold := m.theme.TextAccent().Bold(true).Render
 	base := m.theme.Base().Render
@@ -25,7 +58,16 @@ func (m model) FooterView() string {
 		PaddingBottom(1).
 		Align(lipgloss.Center)
 
+	// a toast takes the place of the commands until it clears
+	toast := ""
+	if m.state.toast.message != "" {
+		toast = m.theme.TextHighlight().Render(m.state.toast.message)
+	}
+
 	if m.size == small && m.hasMenu {
+		if toast != "" {
+			return table.Render(toast)
+		}
 		return table.Render(bold("m") + base(" menu"))
 	}
 
@@ -33,14 +75,14 @@ func (m model) FooterView() string {
 	for _, cmd := range m.state.footer.commands {
 		commands = append(commands, bold(" "+cmd.key+" ")+base(cmd.value+"  "))
 	}
+	row := lipgloss.JoinHorizontal(lipgloss.Center, commands...)
+	if toast != "" {
+		row = toast
+	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Center,
-		"free shipping on US orders over $40",
-		table.Render(
-			lipgloss.JoinHorizontal(
-				lipgloss.Center,
-				commands...,
-			),
-		))
+		m.freeShippingView(),
+		table.Render(row),
+	)
 }
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/fakeapi"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// cmdTimeout bounds how long the harness waits for a command. Commands that
+// don't return in time (cursor blinks, splash delay) are timers and dropped.
+const cmdTimeout = 100 * time.Millisecond
+
+var testSizes = []struct {
+	name   string
+	width  int
+	height int
+}{
+	{"small", 45, 30},
+	{"medium", 60, 30},
+	{"large", 100, 30},
+}
+
+// harness drives a model the way the bubbletea runtime would: messages go
+// through Update and the commands it returns are executed and fed back in.
+type harness struct {
+	t      *testing.T
+	model  tea.Model
+	quit   bool
+	fake   *fakeapi.Server
+	server *httptest.Server
+}
+
+func newHarness(t *testing.T, width int, height int) *harness {
+	t.Helper()
+
+	seed, err := fakeapi.LoadSeed(filepath.Join("testdata", "seed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := fakeapi.New(seed)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	// io.Discard isn't a terminal, so the renderer falls back to plain ascii
+	// and golden files don't contain escape sequences.
+	renderer := lipgloss.NewRenderer(io.Discard)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tm, err := NewModel(renderer, api.Identity{Fingerprint: "SHA256:test"}, logger, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := tm.(model)
+	m.client = terminal.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithBearerToken(fake.SignIn("SHA256:test")),
+	)
+
+	h := &harness{t: t, model: m, fake: fake, server: server}
+	h.send(tea.WindowSizeMsg{Width: width, Height: height})
+
+	// skip the sign in and splash delay, but load data like SplashUpdate does
+	for _, cmd := range m.LoadDataCmds() {
+		h.send(cmd())
+	}
+	h.send(DelayCompleteMsg{})
+	return h
+}
+
+func (h *harness) send(msg tea.Msg) {
+	h.t.Helper()
+
+	queue := []tea.Msg{msg}
+	for len(queue) > 0 && !h.quit {
+		next := queue[0]
+		queue = queue[1:]
+
+		var cmd tea.Cmd
+		h.model, cmd = h.model.Update(next)
+		queue = append(queue, h.run(cmd)...)
+	}
+}
+
+func (h *harness) run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	result := make(chan tea.Msg, 1)
+	go func() { result <- cmd() }()
+
+	var msg tea.Msg
+	select {
+	case msg = <-result:
+	case <-time.After(cmdTimeout):
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case nil:
+		return nil
+	case tea.QuitMsg:
+		h.quit = true
+		return nil
+	case tea.BatchMsg:
+		msgs := []tea.Msg{}
+		for _, cmd := range msg {
+			msgs = append(msgs, h.run(cmd)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func (h *harness) press(keys ...string) {
+	h.t.Helper()
+
+	for _, key := range keys {
+		h.send(keyMsg(key))
+	}
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEscape}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func (h *harness) expect(text string) {
+	h.t.Helper()
+	if view := h.model.View(); !strings.Contains(view, text) {
+		h.t.Errorf("expected %q in\n%s", text, view)
+	}
+}
+
+// golden compares the current view against testdata/<name>.golden, writing
+// it instead when the tests run with -update.
+func (h *harness) golden(name string) {
+	h.t.Helper()
+
+	view := h.model.View()
+	// trailing spaces are noise in diffs and editors like to strip them
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	view = strings.Join(lines, "\n") + "\n"
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			h.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(view), 0o644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if string(expected) != view {
+		h.t.Errorf("%s does not match the view:\n%s", path, view)
+	}
+}
@@ -11,7 +11,7 @@ import (
 func (m model) HeaderUpdate(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.hasMenu {
+		if m.hasMenu && !m.accountFormOpen() {
 			switch msg.String() {
 			case "c":
 				return m.CartSwitch()
@@ -117,7 +117,7 @@ func (m model) HeaderView() string {
 		}
 	}
 
-	return table.New().
+	header := table.New().
 		Border(lipgloss.NormalBorder()).
 		BorderStyle(m.renderer.NewStyle().Foreground(m.theme.Border())).
 		Row(tabs...).
@@ -128,4 +128,9 @@ func (m model) HeaderView() string {
 				AlignHorizontal(lipgloss.Center)
 		}).
 		Render()
+
+	if m.state.drain.draining {
+		return lipgloss.JoinVertical(lipgloss.Left, m.DrainView(), header)
+	}
+	return header
 }
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// idleWarning is how long the countdown is shown before an idle session is
+// disconnected.
+const idleWarning = 30 * time.Second
+
+type idleState struct {
+	// zero disables the timeout
+	timeout time.Duration
+	// last key press or mouse event
+	active    time.Time
+	warning   bool
+	remaining time.Duration
+}
+
+type idleTickMsg time.Time
+
+func (m model) idleCountdown() time.Duration {
+	return time.Duration(math.Min(float64(idleWarning), float64(m.state.idle.timeout/2)))
+}
+
+func (m model) idleTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return idleTickMsg(t)
+	})
+}
+
+func (m model) IdleInit() tea.Cmd {
+	if m.state.idle.timeout == 0 {
+		return nil
+	}
+	return m.idleTick(m.state.idle.timeout - m.idleCountdown())
+}
+
+// IdleUpdate tracks activity and disconnects the session once it's been idle
+// for the timeout. It reports whether it consumed the message, so the key
+// that dismisses the countdown doesn't also act on the page.
+func (m model) IdleUpdate(msg tea.Msg) (model, tea.Cmd, bool) {
+	if m.state.idle.timeout == 0 {
+		return m, nil, false
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.state.idle.active = time.Now()
+		if !m.state.idle.warning {
+			return m, nil, false
+		}
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+c" {
+			return m, nil, false
+		}
+		m.state.idle.warning = false
+		return m, nil, true
+	case idleTickMsg:
+		// only one tick is ever scheduled, activity just moves the deadline
+		idle := time.Time(msg).Sub(m.state.idle.active)
+		remaining := m.state.idle.timeout - idle
+		switch {
+		case remaining <= 0:
+			m.logger.Info("session idle, disconnecting", "idle", idle.Round(time.Second))
+			return m, tea.Quit, true
+		case remaining <= m.idleCountdown():
+			m.state.idle.warning = true
+			m.state.idle.remaining = remaining
+			return m, m.idleTick(time.Duration(math.Min(float64(time.Second), float64(remaining)))), true
+		default:
+			m.state.idle.warning = false
+			return m, m.idleTick(remaining - m.idleCountdown()), true
+		}
+	}
+
+	return m, nil, false
+}
+
+func (m model) IdleView() string {
+	seconds := int(math.Ceil(m.state.idle.remaining.Seconds()))
+	return lipgloss.Place(
+		m.viewportWidth,
+		m.viewportHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		m.CreateCenteredBox(
+			lipgloss.JoinVertical(
+				lipgloss.Center,
+				m.theme.TextAccent().Render("still there?"),
+				"",
+				fmt.Sprintf("disconnecting in %ds", seconds),
+				m.theme.Base().Render("press any key to stay"),
+			),
+			true,
+		),
+	)
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdle(t *testing.T) {
+	h := newHarness(t, 100, 30)
+	m := h.model.(model)
+	m.state.idle.timeout = time.Minute
+	h.model = m
+	before := h.model.View()
+
+	h.send(idleTickMsg(m.state.idle.active.Add(50 * time.Second)))
+	h.golden("idle/countdown")
+
+	// the key dismisses the countdown without adding to the cart
+	h.press("+")
+	if view := h.model.View(); view != before {
+		t.Errorf("expected the shop unchanged after dismissing, got\n%s", view)
+	}
+
+	active := h.model.(model).state.idle.active
+	h.send(idleTickMsg(active.Add(time.Minute)))
+	if !h.quit {
+		t.Error("expected the session to close once idle")
+	}
+}
@@ -0,0 +1,351 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+type KeyPairedMsg struct {
+	pairing api.KeyPairing
+}
+
+type KeyLinkedMsg struct {
+	client      *terminal.Client
+	accessToken string
+}
+
+type keysState struct {
+	selected int
+	deleting *int
+	pairing  *api.KeyPairing
+	form     *huh.Form
+	code     string
+	linking  bool
+	error    string
+}
+
+// LoadKeys loads the linked keys. The key endpoints are newer than the
+// rest, so the shop keeps working without them.
+func (m model) LoadKeys() tea.Cmd {
+	return func() tea.Msg {
+		keys, err := api.ListKeys(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load keys", "error", err)
+			return nil
+		}
+		return keys
+	}
+}
+
+// the boxes after the keys
+func (m model) pairKeyIndex() int {
+	return len(m.keys)
+}
+
+func (m model) enterPairingCodeIndex() int {
+	return len(m.keys) + 1
+}
+
+// canEnterPairingCode is false for sessions signed in with an email code,
+// which have no key to link.
+func (m model) canEnterPairingCode() bool {
+	return m.identity.Credentials == nil
+}
+
+func (m model) isSessionKey(key api.Key) bool {
+	return key.Fingerprint == m.identity.Fingerprint ||
+		(m.identity.Legacy != "" && key.Fingerprint == m.identity.Legacy)
+}
+
+func (m model) nextKey() (model, tea.Cmd) {
+	next := m.state.keys.selected + 1
+	max := m.pairKeyIndex()
+	if m.canEnterPairingCode() {
+		max = m.enterPairingCodeIndex()
+	}
+	if next > max {
+		next = max
+	}
+
+	m.state.keys.selected = next
+	return m, nil
+}
+
+func (m model) previousKey() (model, tea.Cmd) {
+	next := m.state.keys.selected - 1
+	if next < 0 {
+		next = 0
+	}
+
+	m.state.keys.selected = next
+	return m, nil
+}
+
+func (m model) keysFormStart() (model, tea.Cmd) {
+	m.state.keys.code = ""
+	m.state.keys.error = ""
+	m.state.keys.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("pairing code").
+				Key("code").
+				Value(&m.state.keys.code).
+				Validate(validate.NotEmpty("pairing code")),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+
+	return m, m.state.keys.form.Init()
+}
+
+func (m model) keysFormUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && !m.state.keys.linking {
+		m.state.keys.form = nil
+		return m, nil
+	}
+
+	next, cmd := m.state.keys.form.Update(msg)
+	m.state.keys.form = next.(*huh.Form)
+	if m.state.keys.linking || m.state.keys.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.state.keys.linking = true
+	code := strings.TrimSpace(m.state.keys.form.GetString("code"))
+	return m, func() tea.Msg {
+		token, err := api.FetchPairedToken(m.identity, code)
+		if err != nil {
+			m.logger.Warn("could not pair key", "error", err)
+			return VisibleError{message: "that pairing code didn't work, it may have expired"}
+		}
+
+		identity := m.identity
+		identity.Credentials = token
+		client, _, err := api.NewUserClient(identity, m.logger)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return KeyLinkedMsg{client: client, accessToken: token.AccessToken}
+	}
+}
+
+func (m model) KeysUpdate(msg tea.Msg) (model, tea.Cmd) {
+	m.state.footer.commands = []footerCommand{
+		{key: "↑/↓", value: "navigate"},
+		{key: "x/del", value: "revoke"},
+		{key: "esc", value: "back"},
+	}
+
+	switch msg := msg.(type) {
+	case KeyPairedMsg:
+		m.state.keys.pairing = &msg.pairing
+		return m, nil
+	case KeyLinkedMsg:
+		// signed in to the paired account now, load it in place of this one
+		m.client = msg.client
+		m.accessToken = msg.accessToken
+		m.state.keys = keysState{}
+		return m, tea.Batch(
+			func() tea.Msg {
+				response, err := m.client.View.Init(m.context)
+				if err != nil {
+					return VisibleError{message: api.GetErrorMessage(err)}
+				}
+				return response.Data
+			},
+			m.LoadKeys(),
+		)
+	case VisibleError:
+		m.state.keys.form = nil
+		m.state.keys.linking = false
+		m.state.keys.error = msg.message
+		return m, nil
+	}
+
+	if m.state.keys.form != nil {
+		return m.keysFormUpdate(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down", "tab":
+			if m.state.keys.deleting == nil {
+				return m.nextKey()
+			}
+		case "k", "up", "shift+tab":
+			if m.state.keys.deleting == nil {
+				return m.previousKey()
+			}
+		case "delete", "d", "backspace", "x":
+			if m.state.keys.deleting != nil || m.state.keys.selected >= len(m.keys) {
+				return m, nil
+			}
+			if m.isSessionKey(m.keys[m.state.keys.selected]) {
+				m.state.keys.error = "you're signed in with this key, revoke it from another one"
+				return m, nil
+			}
+			m.state.keys.error = ""
+			m.state.keys.deleting = &m.state.keys.selected
+			return m, nil
+		case "y":
+			if m.state.keys.deleting != nil {
+				m.state.keys.deleting = nil
+				id := m.keys[m.state.keys.selected].ID
+				return m, func() tea.Msg {
+					if err := api.DeleteKey(m.context, m.client, id); err != nil {
+						return VisibleError{message: api.GetErrorMessage(err)}
+					}
+					keys, err := api.ListKeys(m.context, m.client)
+					if err != nil {
+						return VisibleError{message: api.GetErrorMessage(err)}
+					}
+					return keys
+				}
+			}
+			return m, nil
+		case "n", "esc":
+			m.state.keys.deleting = nil
+			return m, nil
+		case "enter":
+			if m.state.keys.deleting != nil {
+				return m, nil
+			}
+			switch {
+			case m.state.keys.selected == m.pairKeyIndex():
+				m.state.keys.error = ""
+				return m, func() tea.Msg {
+					pairing, err := api.PairKey(m.context, m.client)
+					if err != nil {
+						return VisibleError{message: api.GetErrorMessage(err)}
+					}
+					return KeyPairedMsg{pairing: pairing}
+				}
+			case m.state.keys.selected == m.enterPairingCodeIndex() && m.canEnterPairingCode():
+				return m.keysFormStart()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// keyName shows a fingerprint the way ssh-keygen -l would.
+func keyName(fingerprint string) string {
+	switch {
+	case strings.HasPrefix(fingerprint, "claim:"):
+		return "claim code"
+	case len(fingerprint) == 32 && !strings.Contains(fingerprint, ":"):
+		// legacy MD5 fingerprints are stored as plain hex
+		pairs := []string{}
+		for i := 0; i < len(fingerprint); i += 2 {
+			pairs = append(pairs, fingerprint[i:i+2])
+		}
+		return "MD5:" + strings.Join(pairs, ":")
+	}
+	return fingerprint
+}
+
+func (m model) formatKey(key api.Key, totalWidth int) string {
+	name := keyName(key.Fingerprint)
+	lines := []string{}
+	if m.isSessionKey(key) {
+		if m.identity.Key != "" {
+			// formatted like ssh-keygen -lf, so shoppers can match it to a key file
+			name = m.identity.Key
+		} else if m.identity.Claim != "" {
+			name = "claim code " + m.identity.Claim
+		}
+	}
+	lines = append(lines, m.theme.TextAccent().Width(totalWidth).Render(name))
+	if m.isSessionKey(key) {
+		lines = append(lines, "this session")
+	}
+
+	lines = append(lines, "linked: "+formatDate(key.Time.Created))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m model) formatPairing(totalWidth int) string {
+	pairing := m.state.keys.pairing
+	expires := "expires soon"
+	if t, err := time.Parse(time.RFC3339, pairing.Expires); err == nil {
+		minutes := int(math.Ceil(time.Until(t).Minutes()))
+		expires = fmt.Sprintf("expires in %d minutes", minutes)
+		if minutes <= 1 {
+			expires = "expires in a minute"
+		}
+	}
+
+	return m.theme.Base().Width(totalWidth).Align(lipgloss.Center).Render(lipgloss.JoinVertical(
+		lipgloss.Center,
+		"enter this code from a session signed in with the other key",
+		m.theme.TextHighlight().Bold(true).Render(pairing.Code),
+		"("+expires+")",
+	))
+}
+
+func (m model) KeysView(totalWidth int, focused bool) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	boxes := []string{}
+	if m.state.keys.error != "" {
+		boxes = append(boxes, m.theme.TextError().Width(totalWidth).Render(m.state.keys.error))
+	}
+
+	for i, key := range m.keys {
+		content := m.formatKey(key, totalWidth-4)
+		if m.state.keys.deleting != nil && *m.state.keys.deleting == i {
+			content = accent("are you sure you want to revoke?") + base("\n(y/n)")
+		}
+		boxes = append(boxes, m.CreateBoxCustom(
+			content,
+			focused && i == m.state.keys.selected,
+			totalWidth,
+		))
+	}
+
+	pair := "pair another key"
+	if m.state.keys.pairing != nil {
+		pair = m.formatPairing(totalWidth - 4)
+	}
+	boxes = append(boxes, m.CreateCenteredBoxCustom(
+		pair,
+		focused && m.state.keys.selected == m.pairKeyIndex(),
+		totalWidth,
+	))
+
+	if m.canEnterPairingCode() {
+		enter := "enter a pairing code"
+		if m.state.keys.linking {
+			enter = "linking..."
+		} else if m.state.keys.form != nil {
+			enter = m.state.keys.form.WithWidth(totalWidth - 4).View()
+		}
+		boxes = append(boxes, m.CreateCenteredBoxCustom(
+			enter,
+			focused && m.state.keys.selected == m.enterPairingCodeIndex(),
+			totalWidth,
+		))
+	}
+
+	if m.identity.Claim != "" {
+		boxes = append(boxes, m.theme.Base().Width(totalWidth).Render(
+			"enter your claim code when you connect without a key to come back to this account",
+		))
+	}
+
+	return m.theme.Base().Render(lipgloss.JoinVertical(lipgloss.Left, boxes...))
+}
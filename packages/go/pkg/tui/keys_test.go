@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/resource"
+)
+
+// openKeys focuses the SSH Keys account page.
+func openKeys(h *harness) {
+	h.press("a", "j", "j", "j", "enter")
+}
+
+// useFakeAuth points sign in and new clients at the fake API for the test.
+func useFakeAuth(h *harness) {
+	authUrl, apiUrl := resource.Resource.Auth.Url, resource.Resource.Api.Url
+	h.t.Cleanup(func() {
+		resource.Resource.Auth.Url, resource.Resource.Api.Url = authUrl, apiUrl
+	})
+	resource.Resource.Auth.Url = h.server.URL + "/auth"
+	resource.Resource.Api.Url = h.server.URL
+}
+
+func TestKeysPairAnother(t *testing.T) {
+	h := newHarness(t, 100, 30)
+	useFakeAuth(h)
+	openKeys(h)
+	h.expect("SHA256:test")
+	h.expect("this session")
+
+	h.press("x")
+	h.expect("you're signed in with this key")
+
+	h.press("j", "enter")
+	pairing := h.model.(model).state.keys.pairing
+	if pairing == nil {
+		t.Fatal("expected a pairing code")
+	}
+	h.expect(pairing.Code)
+
+	// the other machine enters the code
+	other := api.Identity{Fingerprint: "SHA256:other"}
+	if _, err := api.FetchPairedToken(other, pairing.Code); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.FetchPairedToken(other, pairing.Code); err == nil {
+		t.Error("expected the code to only work once")
+	}
+
+	h.send(h.model.(model).LoadKeys()())
+	h.expect("SHA256:other")
+
+	h.press("k", "k", "k")
+	for _, key := range h.model.(model).keys {
+		if key.Fingerprint == "SHA256:other" {
+			break
+		}
+		h.press("j")
+	}
+	h.press("x", "y")
+	if view := h.model.View(); strings.Contains(view, "SHA256:other") {
+		t.Errorf("expected the key to be revoked\n%s", view)
+	}
+}
+
+func TestKeysEnterCode(t *testing.T) {
+	h := newHarness(t, 100, 30)
+	useFakeAuth(h)
+
+	// the other machine creates the code
+	laptop := terminal.NewClient(
+		option.WithBaseURL(h.server.URL),
+		option.WithBearerToken(h.fake.SignIn("SHA256:laptop")),
+	)
+	pairing, err := api.PairKey(context.Background(), laptop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openKeys(h)
+	h.press("j", "j", "enter")
+	for _, char := range pairing.Code {
+		h.press(string(char))
+	}
+	h.press("enter")
+
+	h.expect("SHA256:laptop")
+	h.expect("SHA256:test")
+}
@@ -2,17 +2,37 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type ordersState struct {
 	selected int
+	detail   bool
 	// deleting *int
 }
 
+// LoadOrderDiscounts loads what promos took off the orders, the SDK's orders
+// don't have it yet.
+func (m model) LoadOrderDiscounts() tea.Cmd {
+	return func() tea.Msg {
+		discounts, err := api.GetOrderDiscounts(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load order discounts", "error", err)
+			return nil
+		}
+		return discounts
+	}
+}
+
+func (m model) orderTotal(order terminal.Order) int64 {
+	return order.Amount.Subtotal + order.Amount.Shipping - m.orderDiscounts[order.ID]
+}
+
 func (m model) nextOrder() (model, tea.Cmd) {
 	next := m.state.orders.selected + 1
 	max := len(m.orders) - 1
@@ -35,8 +55,13 @@ func (m model) previousOrder() (model, tea.Cmd) {
 }
 
 func (m model) OrdersUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if m.state.orders.detail {
+		return m.orderDetailUpdate(msg)
+	}
+
 	m.state.footer.commands = []footerCommand{
 		{key: "↑/↓", value: "navigate"},
+		{key: "enter", value: "details"},
 		{key: "esc", value: "back"},
 	}
 
@@ -49,30 +74,64 @@ func (m model) OrdersUpdate(msg tea.Msg) (model, tea.Cmd) {
 			return m.nextOrder()
 		case "k", "up", "shift+tab":
 			return m.previousOrder()
+		case "enter":
+			if len(m.orders) == 0 {
+				return m, nil
+			}
+			m.state.orders.detail = true
+			m.switched = true
+			return m.orderDetailUpdate(nil)
 		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m model) formatOrderItem(orderItem terminal.OrderItem) string {
-	var product *terminal.Product
-	// var variant *terminal.ProductVariant
-	for _, p := range m.products {
-		for _, v := range p.Variants {
-			if v.ID == orderItem.ProductVariantID {
-				product = &p
-				// variant = &v
-			}
+func (m model) orderDetailUpdate(msg tea.Msg) (model, tea.Cmd) {
+	m.state.footer.commands = []footerCommand{
+		{key: "↑/↓", value: "scroll"},
+		{key: "y", value: "copy id"},
+	}
+	order := m.orders[m.state.orders.selected]
+	if order.Tracking.Number != "" {
+		m.state.footer.commands = append(m.state.footer.commands, footerCommand{key: "t", value: "copy tracking"})
+	}
+	m.state.footer.commands = append(m.state.footer.commands, footerCommand{key: "esc", value: "orders"})
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "y":
+			return m, m.Copy("order ID", order.ID)
+		case "t":
+			return m, m.Copy("tracking number", order.Tracking.Number)
+		case "esc", "left", "h", "enter":
+			m.state.orders.detail = false
+			m.switched = true
+			return m.OrdersUpdate(nil)
 		}
 	}
 
-	return fmt.Sprintf("%dx %s", orderItem.Quantity, product.Name)
+	return m, nil
+}
+
+func (m model) getOrderItemName(orderItem terminal.OrderItem) (string, string) {
+	product, variant := m.GetProductVariant(orderItem.ProductVariantID)
+	if product == nil {
+		return orderItem.Description, ""
+	}
+
+	return product.Name, variant.Name
+}
+
+func (m model) formatOrderItem(orderItem terminal.OrderItem) string {
+	name, _ := m.getOrderItemName(orderItem)
+	return fmt.Sprintf("%dx %s", orderItem.Quantity, name)
 }
 
 func (m model) formatOrder(order terminal.Order, totalWidth int, index int) string {
 	orderNumber := fmt.Sprintf("Order #%d", index)
-	price := fmt.Sprintf("$%2v", (order.Amount.Subtotal+order.Amount.Shipping)/100)
+	price := fmt.Sprintf("$%2v", m.orderTotal(order)/100)
 	space := totalWidth - lipgloss.Width(
 		orderNumber,
 	) - lipgloss.Width(price) - 2
@@ -89,17 +148,105 @@ func (m model) formatOrder(order terminal.Order, totalWidth int, index int) stri
 		lines = append(lines, m.formatOrderItem(item))
 	}
 
-	// if index == m.state.orders.selected && m.state.account.focused {
-	// 	lines = append(lines, order.Tracking.Service+" ("+order.Tracking.Number+")")
-	// }
+	if index == len(m.orders)-m.state.orders.selected-1 &&
+		m.state.account.focused &&
+		order.Tracking.Number != "" {
+		lines = append(lines, order.Tracking.Service+" ("+order.Tracking.Number+")")
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+func (m model) orderDetailView(order terminal.Order, totalWidth int, index int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	justify := func(left string, right string) string {
+		space := totalWidth - lipgloss.Width(left) - lipgloss.Width(right) - 2
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			left,
+			m.theme.Base().Width(space).Render(),
+			right,
+		)
+	}
+
+	lines := []string{}
+	created := ""
+	if t, ok := api.GetOrderCreated(order.ID); ok {
+		created = t.Format("Jan 2, 2006 15:04 UTC")
+	}
+	lines = append(lines, justify(accent(fmt.Sprintf("Order #%d", index)), base(created)))
+	lines = append(lines, "")
+
+	address := order.Shipping
+	lines = append(lines, accent("shipping to"))
+	lines = append(lines, address.Name)
+	lines = append(lines, address.Street1)
+	if address.Street2 != "" {
+		lines = append(lines, address.Street2)
+	}
+	lines = append(lines, address.City+", "+address.Province+", "+address.Country+" "+address.Zip)
+	lines = append(lines, "")
+
+	lines = append(lines, accent("tracking"))
+	if order.Tracking.Number != "" {
+		lines = append(lines, order.Tracking.Service)
+		lines = append(lines, order.Tracking.Number)
+		if order.Tracking.URL != "" {
+			lines = append(lines, m.theme.TextHighlight().Render(order.Tracking.URL))
+		}
+	} else {
+		lines = append(lines, "not shipped yet")
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, accent("items"))
+	for _, item := range order.Items {
+		name, variant := m.getOrderItemName(item)
+		lines = append(lines, justify(
+			base(fmt.Sprintf("%dx %s", item.Quantity, name)),
+			base(formatUSD(int(item.Amount))),
+		))
+		details := strings.ToLower(variant)
+		if item.Quantity > 1 {
+			each := fmt.Sprintf("%s each", formatUSD(int(item.Amount/item.Quantity)))
+			if details != "" {
+				details += ", " + each
+			} else {
+				details = each
+			}
+		}
+		if details != "" {
+			lines = append(lines, "   "+details)
+		}
+	}
+	lines = append(lines, "")
+
+	subtotal := int(order.Amount.Subtotal)
+	shipping := int(order.Amount.Shipping)
+	discount := int(m.orderDiscounts[order.ID])
+	lines = append(lines, justify(base("Subtotal"), base(formatUSD(subtotal))))
+	lines = append(lines, justify(base("Shipping"), base(formatUSD(shipping))))
+	if discount > 0 {
+		lines = append(lines, justify(base("Discount"), base("-"+formatUSD(discount))))
+	}
+	lines = append(lines, justify(accent("Total"), accent(formatUSD(int(m.orderTotal(order))))))
+
+	return m.theme.Base().Width(totalWidth).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
+
 func (m model) OrdersView(totalWidth int, focused bool) string {
 	base := m.theme.Base().Render
 	// accent := m.theme.TextAccent().Render
 
+	if focused && m.state.orders.detail && m.state.orders.selected < len(m.orders) {
+		i := m.state.orders.selected
+		return m.orderDetailView(m.orders[i], totalWidth, len(m.orders)-i-1)
+	}
+
 	orders := []string{}
 	for i, order := range m.orders {
 		content := m.formatOrder(order, totalWidth, len(m.orders)-i-1)
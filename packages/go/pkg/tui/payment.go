@@ -7,7 +7,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/log"
 	"github.com/stripe/stripe-go/v78"
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
@@ -42,6 +41,11 @@ type SelectedCardUpdatedMsg struct {
 	cardID string
 }
 
+type CardAddedMsg struct {
+	cardID string
+	cards  []terminal.Card
+}
+
 func (m model) GetSelectedCard() *terminal.Card {
 	if m.IsSubscribing() {
 		for _, card := range m.cards {
@@ -72,7 +76,46 @@ func (m model) PaymentSwitch() (model, tea.Cmd) {
 		{key: "enter", value: "select"},
 	}
 	m.state.payment.submitting = false
-	m.state.payment.form = huh.NewForm(
+	m.state.payment.form = m.paymentForm()
+
+	m.state.payment.view = paymentListView
+	if len(m.cards) == 0 {
+		m.state.payment.view = paymentFormView
+	}
+	if m.GetSelectedCard() == nil {
+		m.state.payment.selected = m.defaultCardIndex()
+	}
+
+	m = m.updatePaymentForm()
+	return m, m.state.payment.form.Init()
+}
+
+// CardsManageSwitch focuses the card list on the account page, where cards
+// are added and the default is picked instead of choosing one for the cart.
+func (m model) CardsManageSwitch() (model, tea.Cmd) {
+	m.state.account.focused = true
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "back"},
+		{key: "↑/↓", value: "cards"},
+		{key: "space", value: "default"},
+		{key: "x/del", value: "remove"},
+	}
+	m.state.payment = paymentState{view: paymentListView}
+	return m, nil
+}
+
+// openPaymentForm shows an empty form for a new card.
+func (m model) openPaymentForm() (model, tea.Cmd) {
+	m.state.payment.submitting = false
+	m.state.payment.input = paymentInput{}
+	m.state.payment.form = m.paymentForm()
+	m.state.payment.view = paymentFormView
+	m = m.updatePaymentForm()
+	return m, m.state.payment.form.Init()
+}
+
+func (m model) paymentForm() *huh.Form {
+	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
 				Title("name").
@@ -141,14 +184,33 @@ func (m model) PaymentSwitch() (model, tea.Cmd) {
 	).
 		WithTheme(m.theme.Form()).
 		WithShowHelp(false)
+}
 
-	m.state.payment.view = paymentListView
-	if len(m.cards) == 0 {
-		m.state.payment.view = paymentFormView
+func (m model) defaultCardIndex() int {
+	for i, card := range m.cards {
+		if card.ID == m.defaults.CardID {
+			return i
+		}
 	}
+	return 0
+}
 
-	m = m.updatePaymentForm()
-	return m, m.state.payment.form.Init()
+func (m model) setDefaultCard() (model, tea.Cmd) {
+	if m.state.payment.selected >= len(m.cards) {
+		return m, nil
+	}
+	id := m.cards[m.state.payment.selected].ID
+	return m, func() tea.Msg {
+		err := api.SetDefaultCard(m.context, m.client, id)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		defaults, err := api.GetDefaults(m.context, m.client)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return defaults
+	}
 }
 
 type VisibleError struct {
@@ -221,6 +283,14 @@ func (m model) SetCard(cardID string) {
 }
 
 func (m model) choosePaymentMethod() (model, tea.Cmd) {
+	if m.page == accountPage {
+		// cards can't be edited, only added
+		if m.state.payment.selected < len(m.cards) {
+			return m, nil
+		}
+		return m.openPaymentForm()
+	}
+
 	if m.state.payment.selected < len(m.cards) { // existing method
 		cardID := m.cards[m.state.payment.selected].ID
 		return m, func() tea.Msg {
@@ -239,6 +309,9 @@ func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 
 	switch msg := msg.(type) {
+	case VisibleError:
+		m.state.payment.error = msg.message
+		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "j", "down", "tab":
@@ -257,7 +330,11 @@ func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "y":
 			if m.state.payment.deleting != nil {
 				m.state.payment.deleting = nil
-				m.client.Card.Delete(m.context, m.cards[m.state.payment.selected].ID)
+				id := m.cards[m.state.payment.selected].ID
+				m.client.Card.Delete(m.context, id)
+				if m.defaults.CardID == id {
+					m.defaults.CardID = ""
+				}
 				if len(m.cards)-1 == 0 && m.page == accountPage {
 					m.state.account.focused = false
 				}
@@ -272,6 +349,10 @@ func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "n":
 			m.state.payment.deleting = nil
 			return m, nil
+		case " ":
+			if m.state.payment.deleting == nil && m.page == accountPage {
+				return m.setDefaultCard()
+			}
 		case "enter":
 			if m.state.payment.deleting == nil {
 				return m.choosePaymentMethod()
@@ -280,7 +361,7 @@ func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 			if m.state.payment.deleting != nil {
 				m.state.payment.deleting = nil
 			} else {
-				return m.ShippingSwitch()
+				return m.paymentBack()
 			}
 		}
 	}
@@ -288,6 +369,15 @@ func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// paymentBack returns to the checkout step before payment, subscriptions
+// skip the delivery step.
+func (m model) paymentBack() (model, tea.Cmd) {
+	if m.IsSubscribing() {
+		return m.ShippingSwitch()
+	}
+	return m.DeliverySwitch()
+}
+
 func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 
@@ -295,36 +385,42 @@ func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
-			if len(m.cards) == 0 {
-				return m.ShippingSwitch()
+			if len(m.cards) == 0 && m.page != accountPage {
+				return m.paymentBack()
 			}
 			m.state.payment.view = paymentListView
 			return m, nil
 		}
 	case *stripe.Token:
 		params := terminal.CardNewParams{Token: terminal.F(msg.ID)}
-		response, err := m.client.Card.New(m.context, params)
-
-		if err != nil {
-			m, cmd := m.PaymentSwitch()
-			m.state.payment.view = paymentFormView
-			m.state.payment.error = api.GetErrorMessage(err)
-			return m, cmd
+		return m, func() tea.Msg {
+			response, err := m.client.Card.New(m.context, params)
+			if err != nil {
+				m.logger.Error("could not add card", "error", err)
+				return VisibleError{message: api.GetErrorMessage(err)}
+			}
+			cards, err := m.client.Card.List(m.context)
+			if err != nil {
+				m.logger.Error("could not list cards", "error", err)
+				return VisibleError{message: api.GetErrorMessage(err)}
+			}
+			return CardAddedMsg{cardID: response.Data, cards: cards.Data}
 		}
 
-		cards, _ := m.client.Card.List(m.context)
-
-		m.cards = cards.Data
+	case CardAddedMsg:
+		m.cards = msg.cards
+		if m.page == accountPage {
+			m.state.payment.view = paymentListView
+			m.state.payment.submitting = false
+			return m, nil
+		}
 		return m, func() tea.Msg {
-			m.SetCard(response.Data)
-			return SelectedCardUpdatedMsg{cardID: response.Data}
+			m.SetCard(msg.cardID)
+			return SelectedCardUpdatedMsg{cardID: msg.cardID}
 		}
 
 	case VisibleError:
-		m, cmd := m.PaymentSwitch()
-		m.state.payment.view = paymentFormView
-		m.state.payment.error = msg.message
-		return m, cmd
+		return m.paymentFormError(msg.message)
 	}
 
 	m = m.updatePaymentForm()
@@ -356,7 +452,7 @@ func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 				AddressZip: stripe.String(m.state.payment.input.zip),
 			})
 			if err != nil {
-				log.Error(*err)
+				m.logger.Error("could not tokenize card", "error", *err)
 				return VisibleError{message: *err}
 			}
 			return result
@@ -375,6 +471,18 @@ func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m model) paymentFormError(message string) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.page == accountPage {
+		m, cmd = m.openPaymentForm()
+	} else {
+		m, cmd = m.PaymentSwitch()
+		m.state.payment.view = paymentFormView
+	}
+	m.state.payment.error = message
+	return m, cmd
+}
+
 func (m model) PaymentUpdate(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case SelectedCardUpdatedMsg:
@@ -393,19 +501,19 @@ func (m model) PaymentUpdate(msg tea.Msg) (model, tea.Cmd) {
 	}
 }
 
-func (m model) PaymentView() string {
+func (m model) PaymentView(totalWidth int, focused bool) string {
 	if m.state.payment.submitting {
-		return m.theme.Base().Width(m.widthContent).Render("verifying payment details...")
+		return m.theme.Base().Width(totalWidth).Render("verifying payment details...")
 	}
 
 	if m.state.payment.view == paymentListView {
-		return m.paymentListView()
+		return m.paymentListView(totalWidth, focused)
 	} else {
 		return m.paymentFormView()
 	}
 }
 
-func (m model) paymentListView() string {
+func (m model) paymentListView(totalWidth int, focused bool) string {
 	base := m.theme.Base().Render
 	accent := m.theme.TextAccent().Render
 	methods := []string{}
@@ -422,36 +530,62 @@ func (m model) paymentListView() string {
 			m.theme.Base().Width(space).Render(),
 			expir,
 		)
-		content := lipgloss.JoinVertical(lipgloss.Left, number, expLine)
+		lines := []string{number, expLine}
+		if card.ID == m.defaults.CardID {
+			lines = append(lines, m.theme.TextHighlight().Render("default"))
+		}
+		content := lipgloss.JoinVertical(lipgloss.Left, lines...)
 		if m.state.payment.deleting != nil && *m.state.payment.deleting == i {
 			content = accent("are you sure?") + base("\n(y/n)")
 		}
 
-		method := m.CreateBox(content, i == m.state.payment.selected)
+		method := m.CreateBoxCustom(
+			content,
+			i == m.state.payment.selected && (focused || m.page != accountPage),
+			totalWidth,
+		)
 		methods = append(methods, method)
 	}
 
 	newInSshIndex := len(m.cards)
-	newInSsh := m.CreateCenteredBox("add payment method", m.state.payment.selected == newInSshIndex)
+	newInSsh := m.CreateCenteredBoxCustom(
+		"add payment method",
+		m.state.payment.selected == newInSshIndex && (focused || m.page != accountPage),
+		totalWidth,
+	)
 	methods = append(methods, newInSsh)
 
-	hint := "use selected payment method"
+	key, hint := "enter ", "use selected payment method"
+	if m.page == accountPage {
+		key, hint = "space ", "use as default"
+	}
 	if m.state.payment.selected == newInSshIndex {
-		hint = "create new payment method here"
+		key, hint = "enter ", "create new payment method here"
 	}
 
-	return m.theme.Base().Render(lipgloss.JoinVertical(
-		lipgloss.Left,
-		m.paymentCostsView(),
+	lines := []string{}
+	if m.page == accountPage {
+		if m.state.payment.error != "" {
+			lines = append(lines, m.theme.TextError().Render(m.state.payment.error))
+		}
+	} else {
+		lines = append(lines, m.paymentCostsView())
+	}
+	lines = append(lines,
 		lipgloss.JoinVertical(lipgloss.Left, methods...),
-		accent("enter ")+base(hint),
-	))
+		accent(key)+base(hint),
+	)
+	return m.theme.Base().Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
 }
 
 func (m model) paymentFormView() string {
+	costs := m.paymentCostsView()
+	if m.page == accountPage {
+		costs = ""
+	}
 	return m.theme.Base().Render(lipgloss.JoinVertical(
 		lipgloss.Left,
-		m.paymentCostsView(),
+		costs,
 		"\ncreate new payment method:\n",
 		m.state.payment.form.View(),
 		m.theme.TextError().Render(m.state.payment.error),
@@ -463,16 +597,21 @@ func (m model) paymentCostsView() string {
 	price := m.cart.Amount.Subtotal
 	shipping := m.cart.Amount.Shipping
 
+	discount := m.CartDiscount()
+
 	if m.IsSubscribing() {
-		price = m.state.subscribe.product.Variants[m.state.subscribe.selected].Price
+		price = m.SubscribePrice()
 		shipping = 0
 	}
 
 	view.WriteString(fmt.Sprintf("Subtotal: %s", formatUSD(int(price))) + ", ")
+	if discount > 0 {
+		view.WriteString(fmt.Sprintf("Discount: -%s", formatUSD(int(discount))) + ", ")
+	}
 	view.WriteString(fmt.Sprintf("Shipping: %s", formatUSD(int(shipping))) + ", ")
 	view.WriteString(
 		m.theme.TextAccent().
-			Render(fmt.Sprintf("Total: %s", formatUSD(int(price+shipping)))),
+			Render(fmt.Sprintf("Total: %s", formatUSD(int(price+shipping-discount)))),
 	)
 
 	return view.String()
@@ -2,14 +2,18 @@ package tui
 
 import (
 	"context"
+	"log/slog"
 	"math"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/metrics"
 	"github.com/terminaldotshop/terminal/go/pkg/tui/theme"
 )
 
@@ -25,15 +29,40 @@ const (
 	cartPage
 	subscribePage
 	shippingPage
+	deliveryPage
 	confirmPage
 	finalPage
 	subscriptionsPage
 	tokensPage
+	keysPage
+	appsPage
 	ordersPage
 	aboutPage
 	faqPage
 )
 
+// pageNames label pages in logs and metrics.
+var pageNames = map[page]string{
+	menuPage:          "menu",
+	splashPage:        "splash",
+	shopPage:          "shop",
+	accountPage:       "account",
+	paymentPage:       "payment",
+	cartPage:          "cart",
+	subscribePage:     "subscribe",
+	shippingPage:      "shipping",
+	deliveryPage:      "delivery",
+	confirmPage:       "confirm",
+	finalPage:         "final",
+	subscriptionsPage: "subscriptions",
+	tokensPage:        "tokens",
+	keysPage:          "keys",
+	appsPage:          "apps",
+	ordersPage:        "orders",
+	aboutPage:         "about",
+	faqPage:           "faq",
+}
+
 const (
 	undersized size = iota
 	small
@@ -42,29 +71,36 @@ const (
 )
 
 type model struct {
-	ready         bool
-	switched      bool
-	page          page
-	hasMenu       bool
-	checkout      bool
-	state         state
-	context       context.Context
-	client        *terminal.Client
-	user          terminal.Profile
-	accountPages  []page
-	products      []terminal.Product
-	addresses     []terminal.Address
-	cards         []terminal.Card
-	subscriptions []terminal.Subscription
-	tokens        []terminal.Token
-	apps          []terminal.App
-	orders        []terminal.Order
-	cart          terminal.Cart
-	subscription  terminal.SubscriptionParam
-	renderer      *lipgloss.Renderer
-	// output          *termenv.Output
+	ready           bool
+	switched        bool
+	page            page
+	hasMenu         bool
+	checkout        bool
+	state           state
+	context         context.Context
+	client          *terminal.Client
+	user            terminal.Profile
+	accountPages    []page
+	products        []terminal.Product
+	addresses       []terminal.Address
+	cards           []terminal.Card
+	defaults        api.Defaults
+	promo           *api.Promo
+	freeShipping    *api.FreeShipping
+	subscriptions   []api.Subscription
+	tokens          []api.Token
+	keys            []api.Key
+	apps            []terminal.App
+	orders          []terminal.Order
+	orderDiscounts  api.OrderDiscounts
+	cart            terminal.Cart
+	subscription    terminal.SubscriptionParam
+	renderer        *lipgloss.Renderer
+	output          *termenv.Output
 	theme           theme.Theme
-	fingerprint     string
+	identity        api.Identity
+	logger          *slog.Logger
+	session         *metrics.Session
 	viewportWidth   int
 	viewportHeight  int
 	widthContainer  int
@@ -83,8 +119,11 @@ type state struct {
 	splash        SplashState
 	cursor        cursorState
 	shipping      shippingState
+	delivery      deliveryState
 	subscriptions subscriptionsState
 	tokens        tokensState
+	keys          keysState
+	apps          appsState
 	orders        ordersState
 	shop          shopState
 	account       accountState
@@ -95,6 +134,9 @@ type state struct {
 	confirm       confirmState
 	faq           faqState
 	menu          menuState
+	drain         drainState
+	idle          idleState
+	toast         toastState
 }
 
 type children struct {
@@ -102,7 +144,10 @@ type children struct {
 
 func NewModel(
 	renderer *lipgloss.Renderer,
-	fingerprint string,
+	identity api.Identity,
+	logger *slog.Logger,
+	session *metrics.Session,
+	idleTimeout time.Duration,
 ) (tea.Model, error) {
 	api.Init()
 
@@ -112,16 +157,20 @@ func NewModel(
 		context:  ctx,
 		page:     splashPage,
 		renderer: renderer,
-		// output:      renderer.Output(),
-		fingerprint: fingerprint,
-		theme:       theme.BasicTheme(renderer, nil),
-		faqs:        LoadFaqs(),
+		output:   renderer.Output(),
+		identity: identity,
+		logger:   logger,
+		session:  session,
+		theme:    theme.BasicTheme(renderer, nil),
+		faqs:     LoadFaqs(),
 		accountPages: []page{
 			ordersPage,
 			subscriptionsPage,
 			tokensPage,
-			// shippingPage,
-			// paymentPage,
+			keysPage,
+			appsPage,
+			shippingPage,
+			paymentPage,
 			faqPage,
 			aboutPage,
 		},
@@ -160,6 +209,10 @@ func NewModel(
 			footer: footerState{
 				commands: []footerCommand{},
 			},
+			idle: idleState{
+				timeout: idleTimeout,
+				active:  time.Now(),
+			},
 		},
 	}
 
@@ -168,7 +221,7 @@ func NewModel(
 }
 
 func (m model) Init() tea.Cmd {
-	return m.SplashInit()
+	return tea.Batch(m.SplashInit(), m.IdleInit())
 }
 
 func (m model) SwitchPage(page page) model {
@@ -178,6 +231,11 @@ func (m model) SwitchPage(page page) model {
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd, consumed := m.IdleUpdate(msg)
+	if consumed {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case error:
 		m.error = &VisibleError{
@@ -234,8 +292,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cart = msg.Cart
 		m.cards = msg.Cards
 		m.addresses = msg.Addresses
-		m.subscriptions = msg.Subscriptions
-		m.tokens = msg.Tokens
+		m.apps = msg.Apps
 		m.orders = msg.Orders
 		m = m.reorderProducts()
 	case terminal.Profile:
@@ -249,15 +306,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cards = msg
 	case []terminal.Address:
 		m.addresses = msg
-	case []terminal.Subscription:
+	case []api.Subscription:
 		m.subscriptions = msg
-	case []terminal.Token:
+	case []api.Token:
 		m.tokens = msg
+	case []api.Key:
+		m.keys = msg
+	case api.Defaults:
+		m.defaults = msg
+	case CartPromoMsg:
+		m.promo = msg.promo
+	case api.CartExtras:
+		m.promo = msg.Promo
+		m.freeShipping = &msg.FreeShipping
+	case []terminal.App:
+		m.apps = msg
 	case []terminal.Order:
 		m.orders = msg
+	case api.OrderDiscounts:
+		m.orderDiscounts = msg
 	}
 
-	var cmd tea.Cmd
 	switch m.page {
 	case menuPage:
 		m, cmd = m.MenuUpdate(msg)
@@ -277,6 +346,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m, cmd = m.PaymentUpdate(msg)
 	case shippingPage:
 		m, cmd = m.ShippingUpdate(msg)
+	case deliveryPage:
+		m, cmd = m.DeliveryUpdate(msg)
 	case confirmPage:
 		m, cmd = m.ConfirmUpdate(msg)
 	case finalPage:
@@ -287,6 +358,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m, headerCmd = m.HeaderUpdate(msg)
 	cmds := []tea.Cmd{headerCmd}
 
+	var drainCmd tea.Cmd
+	m, drainCmd = m.DrainUpdate(msg)
+	cmds = append(cmds, drainCmd)
+
+	var toastCmd tea.Cmd
+	m, toastCmd = m.ToastUpdate(msg)
+	cmds = append(cmds, toastCmd)
+
 	if cmd != nil {
 		cmds = append(cmds, cmd)
 	}
@@ -300,6 +379,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.page == subscribePage ||
 		m.page == paymentPage ||
 		m.page == shippingPage ||
+		m.page == deliveryPage ||
 		m.page == confirmPage
 
 	m.viewport.SetContent(m.getContent())
@@ -310,6 +390,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	cmds = append(cmds, cmd)
 
+	m.session.SetPage(pageNames[m.page])
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -318,6 +400,10 @@ func (m model) View() string {
 		return m.ResizeView()
 	}
 
+	if m.state.idle.warning {
+		return m.IdleView()
+	}
+
 	if m.error != nil {
 		return m.ErrorView()
 	}
@@ -386,9 +472,11 @@ func (m model) getContent() string {
 	case subscribePage:
 		page = m.SubscribeView()
 	case paymentPage:
-		page = m.PaymentView()
+		page = m.PaymentView(m.widthContent-2, false)
 	case shippingPage:
 		page = m.ShippingView(m.widthContent-2, false)
+	case deliveryPage:
+		page = m.DeliveryView(m.widthContent - 2)
 	case confirmPage:
 		page = m.ConfirmView()
 	case finalPage:
@@ -4,7 +4,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/log"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
 	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
@@ -29,9 +28,12 @@ type shippingInput struct {
 }
 
 type shippingState struct {
-	view       shippingView
-	selected   int
-	deleting   *int
+	view     shippingView
+	selected int
+	deleting *int
+	// editing is the ID of the address the form replaces, empty when the
+	// form adds a new one
+	editing    string
 	input      shippingInput
 	form       *huh.Form
 	submitting bool
@@ -56,12 +58,78 @@ func (m model) ShippingSwitch() (model, tea.Cmd) {
 		{key: "enter", value: "select"},
 	}
 	m.state.shipping.submitting = false
-	m.state.shipping.form = huh.NewForm(
+	m.state.shipping.editing = ""
+	if m.state.shipping.input.name == "" {
+		m.state.shipping.input.name = m.user.User.Name
+	}
+	m.state.shipping.form = m.shippingForm()
+
+	m.state.shipping.view = shippingListView
+	if len(m.addresses) == 0 {
+		m.state.shipping.view = shippingFormView
+	}
+	if m.GetSelectedAddress() == nil {
+		m.state.shipping.selected = m.defaultAddressIndex()
+	}
+
+	m = m.updateShippingForm()
+	return m, m.state.shipping.form.Init()
+}
+
+// AddressesManageSwitch focuses the address list on the account page, where
+// addresses are edited and the default is picked instead of choosing one for
+// the cart.
+func (m model) AddressesManageSwitch() (model, tea.Cmd) {
+	m.state.account.focused = true
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "back"},
+		{key: "↑/↓", value: "addresses"},
+		{key: "space", value: "default"},
+		{key: "x/del", value: "remove"},
+		{key: "enter", value: "edit"},
+	}
+	m.state.shipping = shippingState{view: shippingListView}
+	return m, nil
+}
+
+// editAddress opens the form with the fields of the address, or empty for a
+// new one when address is nil.
+func (m model) editAddress(address *terminal.Address) (model, tea.Cmd) {
+	m.state.shipping.error = ""
+	m.state.shipping.editing = ""
+	m.state.shipping.input = shippingInput{name: m.user.User.Name, country: "US"}
+	if address != nil {
+		m.state.shipping.editing = address.ID
+		m.state.shipping.input = shippingInput{
+			name:     address.Name,
+			street1:  address.Street1,
+			street2:  address.Street2,
+			city:     address.City,
+			province: address.Province,
+			country:  address.Country,
+			zip:      address.Zip,
+			phone:    address.Phone,
+		}
+	}
+	return m.openShippingForm()
+}
+
+// openShippingForm shows the form filled in with the current input.
+func (m model) openShippingForm() (model, tea.Cmd) {
+	m.state.shipping.submitting = false
+	m.state.shipping.form = m.shippingForm()
+	m.state.shipping.view = shippingFormView
+	m = m.updateShippingForm()
+	return m, m.state.shipping.form.Init()
+}
+
+func (m model) shippingForm() *huh.Form {
+	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
 				Title("name").
 				Key("name").
-				Value(&m.user.User.Name).
+				Value(&m.state.shipping.input.name).
 				Validate(validate.NotEmpty("name")),
 			huh.NewInput().
 				Title("street 1").
@@ -101,14 +169,33 @@ func (m model) ShippingSwitch() (model, tea.Cmd) {
 	).
 		WithTheme(m.theme.Form()).
 		WithShowHelp(false)
+}
 
-	m.state.shipping.view = shippingListView
-	if len(m.addresses) == 0 {
-		m.state.shipping.view = shippingFormView
+func (m model) defaultAddressIndex() int {
+	for i, address := range m.addresses {
+		if address.ID == m.defaults.AddressID {
+			return i
+		}
 	}
+	return 0
+}
 
-	m = m.updateShippingForm()
-	return m, m.state.shipping.form.Init()
+func (m model) setDefaultAddress() (model, tea.Cmd) {
+	if m.state.shipping.selected >= len(m.addresses) {
+		return m, nil
+	}
+	id := m.addresses[m.state.shipping.selected].ID
+	return m, func() tea.Msg {
+		err := api.SetDefaultAddress(m.context, m.client, id)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		defaults, err := api.GetDefaults(m.context, m.client)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return defaults
+	}
 }
 
 func (m model) updateShippingForm() model {
@@ -178,6 +265,13 @@ func (m model) GetSelectedAddress() *terminal.Address {
 }
 
 func (m model) chooseAddress() (model, tea.Cmd) {
+	if m.page == accountPage {
+		if m.state.shipping.selected < len(m.addresses) {
+			return m.editAddress(&m.addresses[m.state.shipping.selected])
+		}
+		return m.editAddress(nil)
+	}
+
 	if m.state.shipping.selected < len(m.addresses) { // existing address
 		shippingID := m.addresses[m.state.shipping.selected].ID
 
@@ -202,6 +296,10 @@ func (m model) shippingListUpdate(msg tea.Msg) (model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case VisibleError:
+		if m.page == accountPage {
+			m.state.shipping.error = msg.message
+			return m, nil
+		}
 		m, cmd := m.ShippingSwitch()
 		m.state.shipping.view = shippingListView
 		m.state.shipping.error = msg.message
@@ -225,7 +323,11 @@ func (m model) shippingListUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "y":
 			if m.state.shipping.deleting != nil {
 				m.state.shipping.deleting = nil
-				m.client.Address.Delete(m.context, m.addresses[m.state.shipping.selected].ID)
+				id := m.addresses[m.state.shipping.selected].ID
+				m.client.Address.Delete(m.context, id)
+				if m.defaults.AddressID == id {
+					m.defaults.AddressID = ""
+				}
 				if len(m.addresses)-1 == 0 && m.page == accountPage {
 					m.state.account.focused = false
 				}
@@ -240,6 +342,10 @@ func (m model) shippingListUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "n":
 			m.state.shipping.deleting = nil
 			return m, nil
+		case " ":
+			if m.state.shipping.deleting == nil && m.page == accountPage {
+				return m.setDefaultAddress()
+			}
 		case "enter":
 			if m.state.shipping.deleting == nil {
 				return m.chooseAddress()
@@ -248,9 +354,6 @@ func (m model) shippingListUpdate(msg tea.Msg) (model, tea.Cmd) {
 			if m.state.shipping.deleting != nil {
 				m.state.shipping.deleting = nil
 			} else if m.IsSubscribing() {
-				if m.SubscribeItemCount() == 1 {
-					return m.ShopSwitch()
-				}
 				return m.SubscribeSwitch()
 			} else {
 				return m.CartSwitch()
@@ -274,6 +377,12 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 
 	case ShippingAddressAddedMsg:
 		m.addresses = msg.addresses
+		if m.page == accountPage {
+			m.state.shipping.view = shippingListView
+			m.state.shipping.submitting = false
+			m.state.shipping.editing = ""
+			return m, nil
+		}
 
 		return m, func() tea.Msg {
 			err := m.SetShipping(msg.shippingID)
@@ -285,6 +394,11 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 		}
 
 	case VisibleError:
+		if m.page == accountPage {
+			m, cmd := m.openShippingForm()
+			m.state.shipping.error = msg.message
+			return m, cmd
+		}
 		m, cmd := m.ShippingSwitch()
 		m.state.shipping.view = shippingFormView
 		m.state.shipping.error = msg.message
@@ -332,14 +446,28 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 				Zip:      terminal.String(m.state.shipping.input.zip),
 				Phone:    terminal.String(m.state.shipping.input.phone),
 			}
-			response, err := m.client.Address.New(m.context, params)
+			shippingID := m.state.shipping.editing
+			if shippingID != "" {
+				err := api.UpdateAddress(m.context, m.client, shippingID, params)
+				if err != nil {
+					m.logger.Error("could not update address", "error", err)
+					return VisibleError{message: api.GetErrorMessage(err)}
+				}
+			} else {
+				response, err := m.client.Address.New(m.context, params)
+				if err != nil {
+					m.logger.Error("could not add address", "error", err)
+					return VisibleError{message: api.GetErrorMessage(err)}
+				}
+				shippingID = response.Data
+			}
+			addresses, err := m.client.Address.List(m.context)
 			if err != nil {
-				log.Error(err)
+				m.logger.Error("could not list addresses", "error", err)
 				return VisibleError{message: api.GetErrorMessage(err)}
 			}
-			addresses, _ := m.client.Address.List(m.context)
 			return ShippingAddressAddedMsg{
-				shippingID: response.Data,
+				shippingID: shippingID,
 				addresses:  addresses.Data,
 			}
 		}
@@ -353,12 +481,12 @@ func (m model) ShippingUpdate(msg tea.Msg) (model, tea.Cmd) {
 	case SelectedShippingUpdatedMsg:
 		if m.IsSubscribing() {
 			m.subscription.AddressID = terminal.String(msg.shippingID)
-		} else {
-			m.cart.AddressID = msg.shippingID
-			cart, _ := m.client.Cart.Get(m.context)
-			m.cart = cart.Data
+			return m.PaymentSwitch()
 		}
-		return m.PaymentSwitch()
+		m.cart.AddressID = msg.shippingID
+		cart, _ := m.client.Cart.Get(m.context)
+		m.cart = cart.Data
+		return m.DeliverySwitch()
 	}
 
 	if m.state.shipping.view == shippingListView {
@@ -369,6 +497,9 @@ func (m model) ShippingUpdate(msg tea.Msg) (model, tea.Cmd) {
 }
 
 func (m model) ShippingView(totalWidth int, focused bool) string {
+	if m.state.shipping.submitting && m.page == accountPage {
+		return m.theme.Base().Width(totalWidth).Render("saving address...")
+	}
 	if m.state.shipping.submitting {
 		return m.theme.Base().Width(totalWidth).Render("calculating shipping costs...")
 	}
@@ -380,9 +511,20 @@ func (m model) ShippingView(totalWidth int, focused bool) string {
 	}
 }
 
-func (m model) formatAddress(address terminal.Address) string {
+func (m model) formatAddress(address terminal.Address, totalWidth int) string {
+	status := ""
+	if address.ID == m.defaults.AddressID {
+		status = "default"
+	}
+	space := totalWidth - lipgloss.Width(address.Street1) - lipgloss.Width(status) - 2
+
 	lines := []string{}
-	lines = append(lines, m.theme.TextAccent().Render(address.Street1))
+	lines = append(lines, lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		m.theme.TextAccent().Render(address.Street1),
+		m.theme.Base().Width(space).Render(),
+		m.theme.TextHighlight().Render(status),
+	))
 	if address.Street2 != "" {
 		lines = append(lines, address.Street2)
 	}
@@ -398,7 +540,7 @@ func (m model) shippingListView(totalWidth int, focused bool) string {
 
 	addresses := []string{}
 	for i, address := range m.addresses {
-		content := m.formatAddress(address)
+		content := m.formatAddress(address, totalWidth)
 		if m.state.shipping.deleting != nil && *m.state.shipping.deleting == i {
 			content = accent("are you sure?") + base("\n(y/n)")
 		}
@@ -413,12 +555,15 @@ func (m model) shippingListView(totalWidth int, focused bool) string {
 	newAddressIndex := len(m.addresses)
 	newAddress := m.CreateCenteredBoxCustom(
 		"add address",
-		m.state.shipping.selected == newAddressIndex,
+		m.state.shipping.selected == newAddressIndex && (focused || m.page != accountPage),
 		totalWidth,
 	)
 	addresses = append(addresses, newAddress)
 
 	hint := "use selected address"
+	if m.page == accountPage {
+		hint = "edit selected address"
+	}
 	if m.state.shipping.selected == newAddressIndex {
 		hint = "create new address"
 	}
@@ -12,18 +12,31 @@ import (
 
 type shopState struct {
 	selected int
+	variant  int
 }
 
 func (m model) ShopSwitch() (model, tea.Cmd) {
 	m = m.SwitchPage(shopPage)
 	m.state.subscribe.product = nil
+	m = m.updateShopFooter()
+	m = m.UpdateSelectedTheme()
+	return m, nil
+}
 
+func (m model) updateShopFooter() model {
 	m.state.footer.commands = []footerCommand{
 		{key: "+/-", value: "qty"},
 		{key: "c", value: "cart"},
 		{key: "q", value: "quit"},
 	}
 
+	if m.hasVariantPicker() {
+		m.state.footer.commands = append(
+			[]footerCommand{{key: "v", value: "variant"}},
+			m.state.footer.commands...,
+		)
+	}
+
 	if len(m.products) > 1 {
 		m.state.footer.commands = append(
 			[]footerCommand{{key: "↑/↓", value: "products"}},
@@ -31,7 +44,36 @@ func (m model) ShopSwitch() (model, tea.Cmd) {
 		)
 	}
 
-	m = m.UpdateSelectedTheme()
+	return m
+}
+
+// hasVariantPicker reports whether the selected product can be bought in
+// more than one variant without a subscription.
+func (m model) hasVariantPicker() bool {
+	if len(m.products) == 0 {
+		return false
+	}
+	product := m.products[m.state.shop.selected]
+	return product.Subscription != terminal.ProductSubscriptionRequired &&
+		len(product.Variants) > 1
+}
+
+func (m model) selectedVariant() terminal.ProductVariant {
+	product := m.products[m.state.shop.selected]
+	index := m.state.shop.variant
+	if index < 0 || index >= len(product.Variants) {
+		index = 0
+	}
+	return product.Variants[index]
+}
+
+func (m model) nextVariant() (model, tea.Cmd) {
+	if !m.hasVariantPicker() {
+		return m, nil
+	}
+
+	product := m.products[m.state.shop.selected]
+	m.state.shop.variant = (m.state.shop.variant + 1) % len(product.Variants)
 	return m, nil
 }
 
@@ -44,17 +86,19 @@ func (m model) ShopUpdate(msg tea.Msg) (model, tea.Cmd) {
 			return m.UpdateSelected(false)
 		case "shift+tab", "up", "k":
 			return m.UpdateSelected(true)
+		case "v":
+			return m.nextVariant()
 		case "+", "=", "right", "l":
 			if product.Subscription == terminal.ProductSubscriptionRequired {
 				return m, nil
 			}
-			productVariantID := m.products[m.state.shop.selected].Variants[0].ID
+			productVariantID := m.selectedVariant().ID
 			return m.UpdateCart(productVariantID, 1)
 		case "-", "left", "h":
 			if product.Subscription == terminal.ProductSubscriptionRequired {
 				return m, nil
 			}
-			productVariantID := m.products[m.state.shop.selected].Variants[0].ID
+			productVariantID := m.selectedVariant().ID
 			return m.UpdateCart(productVariantID, -1)
 		case "enter":
 			if product.Subscription == terminal.ProductSubscriptionRequired {
@@ -69,7 +113,7 @@ func (m model) ShopUpdate(msg tea.Msg) (model, tea.Cmd) {
 				if subscribed {
 					return m.SubscriptionManageSwitch(product.ID)
 				} else {
-					m.state.subscribe.product = &product
+					m.state.subscribe = subscribeState{product: &product}
 					return m.SubscribeSwitch()
 				}
 			}
@@ -96,7 +140,12 @@ func (m model) UpdateSelected(previous bool) (model, tea.Cmd) {
 		next = max
 	}
 
+	if next != m.state.shop.selected {
+		m.state.shop.variant = 0
+	}
+
 	m.state.shop.selected = next
+	m = m.updateShopFooter()
 	m = m.UpdateSelectedTheme()
 	return m, nil
 }
@@ -119,6 +168,7 @@ func (m model) reorderProducts() model {
 	// Reset selection to avoid any out-of-bounds issues
 	if len(m.products) > 0 {
 		m.state.shop.selected = 0
+		m.state.shop.variant = 0
 	}
 
 	return m
@@ -136,8 +186,8 @@ func (m model) ShopView() string {
 		Foreground(m.theme.Background()).
 		Render
 	product := m.products[m.state.shop.selected]
-	variantID := product.Variants[0].ID
-	cartItem, _ := m.GetCartItem(variantID)
+	variant := m.selectedVariant()
+	cartItem, _ := m.GetCartItem(variant.ID)
 	minus := base("- ")
 	plus := base(" +")
 	count := accent(fmt.Sprintf(" %d ", cartItem.Quantity))
@@ -278,20 +328,34 @@ func (m model) ShopView() string {
 
 	name := accent(product.Name)
 	variantNames := ""
-	for _, variant := range product.Variants {
-		if variant.Name == product.Variants[len(product.Variants)-1].Name {
-			variantNames += variant.Name
-		} else {
-			variantNames += variant.Name + "/"
+	if m.hasVariantPicker() {
+		for i, v := range product.Variants {
+			if i > 0 {
+				variantNames += base("/")
+			}
+			if v.ID == variant.ID {
+				variantNames += accent(strings.ToLower(v.Name))
+			} else {
+				variantNames += base(strings.ToLower(v.Name))
+			}
+		}
+	} else {
+		for _, v := range product.Variants {
+			if v.Name == product.Variants[len(product.Variants)-1].Name {
+				variantNames += v.Name
+			} else {
+				variantNames += v.Name + "/"
+			}
 		}
+		variantNames = base(strings.ToLower(variantNames))
 	}
 
 	detail := lipgloss.JoinVertical(
 		lipgloss.Left,
 		name,
-		base(strings.ToLower(variantNames)),
+		variantNames,
 		"",
-		bold(fmt.Sprintf("$%.2v", product.Variants[0].Price/100)),
+		bold(fmt.Sprintf("$%.2v", variant.Price/100)),
 		"",
 		product.Description,
 		"\n",
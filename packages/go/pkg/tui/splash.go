@@ -6,9 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
-	"github.com/terminaldotshop/terminal-sdk-go/option"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
-	"github.com/terminaldotshop/terminal/go/pkg/resource"
 )
 
 type SplashState struct {
@@ -24,12 +22,16 @@ type UserSignedInMsg struct {
 type DelayCompleteMsg struct{}
 
 func (m model) LoadCmds() []tea.Cmd {
-	cmds := []tea.Cmd{}
-
 	// Make sure the loading state shows for at least a couple seconds
-	cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+	delay := tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
 		return DelayCompleteMsg{}
-	}))
+	})
+	return append([]tea.Cmd{delay}, m.LoadDataCmds()...)
+}
+
+// LoadDataCmds loads everything the shop shows, LoadCmds without the delay.
+func (m model) LoadDataCmds() []tea.Cmd {
+	cmds := []tea.Cmd{}
 
 	cmds = append(cmds, func() tea.Msg {
 		response, err := m.client.View.Init(m.context)
@@ -37,6 +39,12 @@ func (m model) LoadCmds() []tea.Cmd {
 		}
 		return response.Data
 	})
+	cmds = append(cmds, m.LoadTokens())
+	cmds = append(cmds, m.LoadKeys())
+	cmds = append(cmds, m.LoadDefaults())
+	cmds = append(cmds, m.LoadCartExtras())
+	cmds = append(cmds, m.LoadSubscriptions())
+	cmds = append(cmds, m.LoadOrderDiscounts())
 
 	return cmds
 }
@@ -49,16 +57,11 @@ func (m model) IsLoadingComplete() bool {
 func (m model) SplashInit() tea.Cmd {
 	cmd := func() tea.Msg {
 		// TODO: error handling
-		token, err := api.FetchUserToken(m.fingerprint)
+		client, token, err := api.NewUserClient(m.identity, m.logger)
 		if err != nil {
 			return tea.Quit
 		}
 
-		client := terminal.NewClient(
-			option.WithBaseURL(resource.Resource.Api.Url),
-			option.WithBearerToken(token.AccessToken),
-		)
-
 		return UserSignedInMsg{
 			accessToken: token.AccessToken,
 			client:      client,
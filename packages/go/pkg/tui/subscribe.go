@@ -6,14 +6,58 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type subscribeState struct {
 	product      *terminal.Product
 	selected     int
+	quantity     int64
+	frequency    terminal.SubscriptionFrequency
 	lastUpdateID int64
 }
 
+// subscribeFrequencies are the cadences a subscription can be started with,
+// from most to least often.
+var subscribeFrequencies = []terminal.SubscriptionFrequency{
+	terminal.SubscriptionFrequencyWeekly,
+	api.SubscriptionFrequencyBiweekly,
+	terminal.SubscriptionFrequencyMonthly,
+}
+
+const maxSubscribeQuantity = 10
+
+// formatFrequency describes how often a subscription ships. Fixed is the
+// monthly schedule subscriptions had before there was a choice.
+func formatFrequency(frequency terminal.SubscriptionFrequency) string {
+	switch frequency {
+	case terminal.SubscriptionFrequencyDaily:
+		return "daily"
+	case terminal.SubscriptionFrequencyWeekly:
+		return "weekly"
+	case api.SubscriptionFrequencyBiweekly:
+		return "every two weeks"
+	case terminal.SubscriptionFrequencyYearly:
+		return "yearly"
+	}
+	return "monthly"
+}
+
+// frequencyUnit is the short suffix for a price paid on every shipment.
+func frequencyUnit(frequency terminal.SubscriptionFrequency) string {
+	switch frequency {
+	case terminal.SubscriptionFrequencyDaily:
+		return "/day"
+	case terminal.SubscriptionFrequencyWeekly:
+		return "/wk"
+	case api.SubscriptionFrequencyBiweekly:
+		return "/2wk"
+	case terminal.SubscriptionFrequencyYearly:
+		return "/yr"
+	}
+	return "/mo"
+}
+
 func (m model) VisibleSubscribeItems() []terminal.ProductVariant {
 	items := []terminal.ProductVariant{}
 	if m.state.subscribe.product == nil {
@@ -65,19 +109,63 @@ func (m model) SubscribeSwitch() (model, tea.Cmd) {
 	m.state.footer.commands = []footerCommand{
 		{key: "esc", value: "back"},
 		{key: "↑/↓", value: "roast"},
+		{key: "+/-", value: "qty"},
+		{key: "←/→", value: "cadence"},
 		{key: "enter", value: "select"},
 	}
 
-	if m.SubscribeItemCount() == 1 {
-		m.subscription.ProductVariantID = terminal.String(
-			m.VisibleSubscribeItems()[0].ID,
-		)
-		return m.ShippingSwitch()
+	if m.state.subscribe.quantity == 0 {
+		m.state.subscribe.quantity = 1
+	}
+	if m.state.subscribe.frequency == "" {
+		m.state.subscribe.frequency = terminal.SubscriptionFrequencyMonthly
 	}
 
 	return m, nil
 }
 
+func (m model) updateSubscribeQuantity(delta int64) (model, tea.Cmd) {
+	next := m.state.subscribe.quantity + delta
+	if next < 1 {
+		next = 1
+	}
+	if next > maxSubscribeQuantity {
+		next = maxSubscribeQuantity
+	}
+	m.state.subscribe.quantity = next
+	return m, nil
+}
+
+func (m model) updateSubscribeFrequency(previous bool) (model, tea.Cmd) {
+	current := 0
+	for i, frequency := range subscribeFrequencies {
+		if frequency == m.state.subscribe.frequency {
+			current = i
+		}
+	}
+
+	next := current + 1
+	if previous {
+		next = current - 1
+	}
+	if next < 0 {
+		next = 0
+	}
+	if next > len(subscribeFrequencies)-1 {
+		next = len(subscribeFrequencies) - 1
+	}
+
+	m.state.subscribe.frequency = subscribeFrequencies[next]
+	return m, nil
+}
+
+// SubscribePrice is what every shipment of the subscription being started
+// costs.
+func (m model) SubscribePrice() int64 {
+	variant := m.VisibleSubscribeItems()[m.state.subscribe.selected]
+	return variant.Price * m.state.subscribe.quantity
+}
+
 type SubscribeUpdatedMsg struct {
 	updateID int64
 	updated  terminal.Cart
@@ -91,15 +179,24 @@ func (m model) SubscribeUpdate(msg tea.Msg) (model, tea.Cmd) {
 			return m.UpdateSelectedSubscribeItem(false)
 		case "k", "up", "shift+tab":
 			return m.UpdateSelectedSubscribeItem(true)
+		case "+", "=":
+			return m.updateSubscribeQuantity(1)
+		case "-":
+			return m.updateSubscribeQuantity(-1)
+		case "right", "l":
+			return m.updateSubscribeFrequency(false)
+		case "left", "h":
+			return m.updateSubscribeFrequency(true)
 		case "enter", "c":
 			if !m.IsSubscribing() {
 				return m, nil
 			}
 			m.subscription.ProductVariantID = terminal.String(m.VisibleSubscribeItems()[m.state.subscribe.selected].ID)
+			m.subscription.Quantity = terminal.Int(m.state.subscribe.quantity)
+			m.subscription.Frequency = terminal.F(m.state.subscribe.frequency)
 			return m.ShippingSwitch()
 		case "esc":
-			m.state.subscribe.selected = 0
-			m.state.subscribe.product = nil
+			m.state.subscribe = subscribeState{}
 			m.subscription = terminal.SubscriptionParam{}
 			return m.ShopSwitch()
 		}
@@ -122,10 +219,12 @@ func (m model) SubscribeView() string {
 		)
 	}
 
+	unit := frequencyUnit(m.state.subscribe.frequency)
+
 	var lines []string
 	for i, item := range m.VisibleSubscribeItems() {
 		name := accent(item.Name)
-		subtotal := m.theme.Base().Render(fmt.Sprintf("$%v/mo", item.Price/100))
+		subtotal := m.theme.Base().Render(fmt.Sprintf("$%v%s", item.Price/100, unit))
 		space := m.widthContent - lipgloss.Width(
 			name,
 		) - lipgloss.Width(
@@ -146,6 +245,11 @@ func (m model) SubscribeView() string {
 		lines = append(lines, line)
 	}
 
+	lines = append(lines, "")
+	lines = append(lines, base("quantity: ")+accent(fmt.Sprintf("‹ %d ›", m.state.subscribe.quantity)))
+	lines = append(lines, base("ships:    ")+accent("‹ "+formatFrequency(m.state.subscribe.frequency)+" ›"))
+	lines = append(lines, base("total:    ")+accent(formatUSD(int(m.SubscribePrice())))+base(" "+formatFrequency(m.state.subscribe.frequency)))
+
 	return m.theme.Base().Render(lipgloss.JoinVertical(
 		lipgloss.Left,
 		lines...,
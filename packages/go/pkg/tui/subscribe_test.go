@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestSubscribeCadence(t *testing.T) {
+	h := newHarness(t, 100, 40)
+
+	// the seed has no subscription products, start the flow on one directly
+	m := h.model.(model)
+	product := m.products[0]
+	product.Subscription = terminal.ProductSubscriptionRequired
+	m.state.subscribe = subscribeState{product: &product}
+	h.model, _ = m.SubscribeSwitch()
+	h.expect("$22/mo")
+	h.expect("subscribe monthly")
+
+	h.press("+", "h")
+	h.expect("‹ 2 ›")
+	h.expect("$22/2wk")
+	h.expect("subscribe every two weeks")
+	h.expect("$44.00 every two weeks")
+
+	h.press("enter", "enter", "enter")
+	h.expect("Quantity: 2, ships every two weeks")
+	h.expect("Subtotal: $44.00")
+
+	h.press("enter")
+	m = h.model.(model)
+	subscriptions, err := m.client.Subscription.List(m.context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions.Data) != 1 {
+		t.Fatalf("expected a subscription, got %+v", subscriptions.Data)
+	}
+	subscription := subscriptions.Data[0]
+	if subscription.Quantity != 2 || subscription.Frequency != api.SubscriptionFrequencyBiweekly {
+		t.Errorf("expected 2 every two weeks, got %d %s", subscription.Quantity, subscription.Frequency)
+	}
+}
+
+func TestSubscriptionsManage(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	m := h.model.(model)
+	_, err := m.client.Subscription.New(m.context, terminal.SubscriptionNewParams{
+		Subscription: terminal.SubscriptionParam{
+			ProductVariantID: terminal.String("var_flow_12oz"),
+			Quantity:         terminal.Int(1),
+			Frequency:        terminal.F(terminal.SubscriptionFrequencyWeekly),
+			AddressID:        terminal.String("shp_test"),
+			CardID:           terminal.String("crd_test"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.send(m.LoadSubscriptions()())
+	subscription := func() api.Subscription {
+		t.Helper()
+		subscriptions := h.model.(model).subscriptions
+		if len(subscriptions) != 1 {
+			t.Fatalf("expected a subscription, got %+v", subscriptions)
+		}
+		return subscriptions[0]
+	}
+	next := subscription().Next
+
+	h.press("a", "j", "enter")
+	h.expect("next shipment: " + formatDate(next))
+
+	h.press("f")
+	skipped := subscription().Next
+	if skipped == next {
+		t.Errorf("expected the next shipment to move, still %s", next)
+	}
+	h.expect("next shipment: " + formatDate(skipped))
+
+	h.press("p")
+	if !subscription().Paused {
+		t.Error("expected the subscription to be paused")
+	}
+	h.expect("paused")
+	h.press("p")
+	if subscription().Paused {
+		t.Error("expected the subscription to be resumed")
+	}
+
+	// the variant select comes first, pick the 2lb bag and keep the rest
+	h.press("e")
+	h.expect("variant")
+	h.press("down", "enter", "enter", "enter")
+	if id := subscription().ProductVariantID; id != "var_flow_2lb" {
+		t.Errorf("expected the variant to change to var_flow_2lb, got %s", id)
+	}
+	h.expect("2lb × 1")
+}
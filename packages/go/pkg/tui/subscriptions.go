@@ -1,25 +1,51 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type subscriptionsState struct {
 	selected int
 	deleting *int
+	// form changes the variant, address and card of the selected subscription
+	form   *huh.Form
+	input  api.SubscriptionParams
+	saving bool
+	error  string
+}
+
+var subscriptionsCommands = []footerCommand{
+	{key: "↑/↓", value: "navigate"},
+	{key: "p", value: "pause/resume"},
+	{key: "f", value: "skip next"},
+	{key: "e", value: "edit"},
+	{key: "x/del", value: "cancel"},
+	{key: "esc", value: "back"},
+}
+
+// LoadSubscriptions loads the subscriptions with their schedule, which the
+// SDK's subscriptions don't have.
+func (m model) LoadSubscriptions() tea.Cmd {
+	return func() tea.Msg {
+		subscriptions, err := api.ListSubscriptions(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load subscriptions", "error", err)
+			return nil
+		}
+		return subscriptions
+	}
 }
 
 func (m model) SubscriptionManageSwitch(id string) (model, tea.Cmd) {
 	m = m.SwitchPage(accountPage)
-	m.state.footer.commands = []footerCommand{
-		{key: "↑/↓", value: "navigate"},
-		{key: "x/del", value: "cancel"},
-		{key: "esc", value: "back"},
-	}
+	m.state.footer.commands = subscriptionsCommands
 	for i, page := range m.accountPages {
 		if page == subscriptionsPage {
 			m.state.account.selected = i
@@ -36,6 +62,8 @@ func (m model) SubscriptionManageSwitch(id string) (model, tea.Cmd) {
 	}
 
 	m.state.subscriptions.deleting = nil
+	m.state.subscriptions.form = nil
+	m.state.subscriptions.error = ""
 	return m, nil
 }
 
@@ -60,15 +88,132 @@ func (m model) previousSubscription() (model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) SubscriptionsUpdate(msg tea.Msg) (model, tea.Cmd) {
-	m.state.footer.commands = []footerCommand{
-		{key: "↑/↓", value: "navigate"},
-		{key: "x/del", value: "cancel"},
-		{key: "esc", value: "back"},
+// subscriptionAction runs an action on the selected subscription and reloads
+// the list, which has the new schedule.
+func (m model) subscriptionAction(
+	action func(context.Context, *terminal.Client, string) error,
+) (model, tea.Cmd) {
+	if m.state.subscriptions.selected >= len(m.subscriptions) {
+		return m, nil
+	}
+	id := m.subscriptions[m.state.subscriptions.selected].ID
+	m.state.subscriptions.error = ""
+	return m, func() tea.Msg {
+		if err := action(m.context, m.client, id); err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return m.LoadSubscriptions()()
+	}
+}
+
+func (m model) subscriptionsFormStart() (model, tea.Cmd) {
+	if m.state.subscriptions.selected >= len(m.subscriptions) {
+		return m, nil
+	}
+	subscription := m.subscriptions[m.state.subscriptions.selected]
+	m.state.subscriptions.error = ""
+	m.state.subscriptions.input = api.SubscriptionParams{
+		AddressID:        subscription.AddressID,
+		CardID:           subscription.CardID,
+		ProductVariantID: subscription.ProductVariantID,
+	}
+
+	variants := []huh.Option[string]{}
+	for _, product := range m.products {
+		for _, variant := range product.Variants {
+			if variant.ID == subscription.ProductVariantID {
+				for _, v := range product.Variants {
+					variants = append(variants, huh.NewOption(v.Name, v.ID))
+				}
+			}
+		}
+	}
+	addresses := []huh.Option[string]{}
+	for _, address := range m.addresses {
+		addresses = append(addresses, huh.NewOption(address.Street1+", "+address.City, address.ID))
+	}
+	cards := []huh.Option[string]{}
+	for _, card := range m.cards {
+		cards = append(cards, huh.NewOption(card.Brand+" "+formatLast4(card.Last4), card.ID))
+	}
+
+	input := &m.state.subscriptions.input
+	m.state.subscriptions.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("variant").
+				Key("variant").
+				Options(variants...).
+				Value(&input.ProductVariantID),
+			huh.NewSelect[string]().
+				Title("address").
+				Key("address").
+				Options(addresses...).
+				Value(&input.AddressID),
+			huh.NewSelect[string]().
+				Title("card").
+				Key("card").
+				Options(cards...).
+				Value(&input.CardID),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+
+	return m, m.state.subscriptions.form.Init()
+}
+
+func (m model) subscriptionsFormUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && !m.state.subscriptions.saving {
+		m.state.subscriptions.form = nil
+		return m, nil
+	}
+
+	next, cmd := m.state.subscriptions.form.Update(msg)
+	m.state.subscriptions.form = next.(*huh.Form)
+	if m.state.subscriptions.saving || m.state.subscriptions.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.state.subscriptions.saving = true
+	form := m.state.subscriptions.form
+	id := m.subscriptions[m.state.subscriptions.selected].ID
+	params := api.SubscriptionParams{
+		ProductVariantID: form.GetString("variant"),
+		AddressID:        form.GetString("address"),
+		CardID:           form.GetString("card"),
 	}
 
+	return m, func() tea.Msg {
+		err := api.UpdateSubscription(m.context, m.client, id, params)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		return m.LoadSubscriptions()()
+	}
+}
+
+func (m model) SubscriptionsUpdate(msg tea.Msg) (model, tea.Cmd) {
+	m.state.footer.commands = subscriptionsCommands
+
 	cmds := []tea.Cmd{}
 
+	switch msg := msg.(type) {
+	case []api.Subscription:
+		m.state.subscriptions.form = nil
+		m.state.subscriptions.saving = false
+		return m, nil
+	case VisibleError:
+		m.state.subscriptions.form = nil
+		m.state.subscriptions.saving = false
+		m.state.subscriptions.error = msg.message
+		return m, nil
+	}
+
+	if m.state.subscriptions.form != nil {
+		return m.subscriptionsFormUpdate(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -92,24 +237,34 @@ func (m model) SubscriptionsUpdate(msg tea.Msg) (model, tea.Cmd) {
 				if len(m.subscriptions)-1 == 0 {
 					m.state.account.focused = false
 				}
-				return m, func() tea.Msg {
-					subscriptions, err := m.client.Subscription.List(m.context)
-					if err != nil {
-					}
-					return subscriptions.Data
-				}
+				return m, m.LoadSubscriptions()
 			}
 			return m, nil
 		case "n", "esc":
 			m.state.subscriptions.deleting = nil
 			return m, nil
+		case "p":
+			if m.state.subscriptions.deleting == nil && m.state.subscriptions.selected < len(m.subscriptions) {
+				if m.subscriptions[m.state.subscriptions.selected].Paused {
+					return m.subscriptionAction(api.ResumeSubscription)
+				}
+				return m.subscriptionAction(api.PauseSubscription)
+			}
+		case "f":
+			if m.state.subscriptions.deleting == nil {
+				return m.subscriptionAction(api.SkipSubscription)
+			}
+		case "e":
+			if m.state.subscriptions.deleting == nil {
+				return m.subscriptionsFormStart()
+			}
 		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m model) formatSubscription(subscription terminal.Subscription, totalWidth int) string {
+func (m model) formatSubscription(subscription api.Subscription, totalWidth int) string {
 	var product *terminal.Product
 	var variant *terminal.ProductVariant
 	for _, p := range m.products {
@@ -121,7 +276,11 @@ func (m model) formatSubscription(subscription terminal.Subscription, totalWidth
 		}
 	}
 
-	price := fmt.Sprintf(" $%2v/mo", variant.Price/100)
+	price := fmt.Sprintf(
+		" $%2v%s",
+		variant.Price*subscription.Quantity/100,
+		frequencyUnit(subscription.Frequency),
+	)
 	space := totalWidth - lipgloss.Width(
 		product.Name,
 	) - lipgloss.Width(price) - 2
@@ -134,7 +293,14 @@ func (m model) formatSubscription(subscription terminal.Subscription, totalWidth
 
 	lines := []string{}
 	lines = append(lines, content)
-	lines = append(lines, variant.Name)
+	lines = append(lines, fmt.Sprintf("%s × %d", variant.Name, subscription.Quantity))
+	lines = append(lines, "ships "+formatFrequency(subscription.Frequency))
+	switch {
+	case subscription.Paused:
+		lines = append(lines, m.theme.TextError().Render("paused"))
+	case subscription.Next != "":
+		lines = append(lines, "next shipment: "+formatDate(subscription.Next))
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -149,6 +315,14 @@ func (m model) SubscriptionsView(totalWidth int, focused bool) string {
 		if m.state.subscriptions.deleting != nil && *m.state.subscriptions.deleting == i {
 			content = accent("are you sure?") + base("\n(y/n)")
 		}
+		if m.state.subscriptions.form != nil && i == m.state.subscriptions.selected {
+			content = lipgloss.JoinVertical(
+				lipgloss.Left,
+				content,
+				"",
+				m.state.subscriptions.form.View(),
+			)
+		}
 		box := m.CreateBoxCustom(
 			content,
 			focused && i == m.state.subscriptions.selected,
@@ -168,6 +342,14 @@ func (m model) SubscriptionsView(totalWidth int, focused bool) string {
 		)
 	}
 
+	if m.state.subscriptions.error != "" {
+		subscriptionList = lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.theme.TextError().Render(m.state.subscriptions.error),
+			subscriptionList,
+		)
+	}
+
 	return m.theme.Base().Render(lipgloss.JoinVertical(
 		lipgloss.Left,
 		subscriptionList,
@@ -1,7 +1,12 @@
 package tui
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
@@ -9,13 +14,30 @@ import (
 
 type TokenAddedMsg struct {
 	newToken terminal.TokenNewResponseData
-	tokens   []terminal.Token
+	tokens   []api.Token
 }
 
 type tokensState struct {
 	selected int
 	deleting *int
 	newToken *terminal.TokenNewResponseData
+	form     *huh.Form
+	creating bool
+	error    string
+}
+
+// tokenExpiries are the expiry choices in days, zero never expires.
+var tokenExpiries = []int{0, 7, 30, 90, 365}
+
+func (m model) LoadTokens() tea.Cmd {
+	return func() tea.Msg {
+		tokens, err := api.ListTokens(m.context, m.client)
+		if err != nil {
+			m.logger.Error("could not load tokens", "error", err)
+			return nil
+		}
+		return tokens
+	}
 }
 
 func (m model) nextToken() (model, tea.Cmd) {
@@ -39,14 +61,150 @@ func (m model) previousToken() (model, tea.Cmd) {
 	return m, nil
 }
 
+// isNewTokenSelected is true while the token that was just created, and is
+// still shown in full, is selected.
+func (m model) isNewTokenSelected() bool {
+	return m.state.tokens.newToken != nil &&
+		m.state.tokens.selected < len(m.tokens) &&
+		m.tokens[m.state.tokens.selected].ID == m.state.tokens.newToken.ID
+}
+
+func scopeName(scope api.TokenScope) string {
+	switch scope {
+	case api.TokenScopeRead:
+		return "read-only orders"
+	case api.TokenScopeCart:
+		return "cart management"
+	}
+	return "full access"
+}
+
+func expiryName(days int) string {
+	switch {
+	case days == 0:
+		return "never"
+	case days == 365:
+		return "in a year"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
+
+func (m model) tokensFormStart() (model, tea.Cmd) {
+	m.state.tokens.error = ""
+	m.state.tokens.newToken = nil
+
+	scopes := []huh.Option[api.TokenScope]{}
+	for _, scope := range []api.TokenScope{api.TokenScopeRead, api.TokenScopeCart, api.TokenScopeFull} {
+		scopes = append(scopes, huh.NewOption(scopeName(scope), scope))
+	}
+	expiries := []huh.Option[int]{}
+	for _, days := range tokenExpiries {
+		expiries = append(expiries, huh.NewOption(expiryName(days), days))
+	}
+
+	m.state.tokens.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("label").
+				Key("label").
+				Placeholder("what's it for?"),
+			huh.NewSelect[api.TokenScope]().
+				Title("scope").
+				Key("scope").
+				Options(scopes...),
+			huh.NewSelect[int]().
+				Title("expires").
+				Key("expires").
+				Options(expiries...),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+
+	return m, m.state.tokens.form.Init()
+}
+
+func (m model) tokensFormUpdate(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && !m.state.tokens.creating {
+		m.state.tokens.form = nil
+		return m, nil
+	}
+
+	next, cmd := m.state.tokens.form.Update(msg)
+	m.state.tokens.form = next.(*huh.Form)
+	if m.state.tokens.creating || m.state.tokens.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.state.tokens.creating = true
+	form := m.state.tokens.form
+	params := api.TokenParams{
+		Label: strings.TrimSpace(form.GetString("label")),
+	}
+	if scope, ok := form.Get("scope").(api.TokenScope); ok {
+		params.Scope = scope
+	}
+	if days := form.GetInt("expires"); days > 0 {
+		expires := time.Now().AddDate(0, 0, days)
+		params.Expires = &expires
+	}
+
+	return m, func() tea.Msg {
+		response, err := api.NewToken(m.context, m.client, params)
+		if err != nil {
+			return VisibleError{message: api.GetErrorMessage(err)}
+		}
+		tokens, err := api.ListTokens(m.context, m.client)
+		if err != nil {
+			// the token was created, show it with the tokens already listed
+			m.logger.Error("could not list tokens", "error", err)
+			token := api.Token{ID: response.ID, Label: params.Label, Scope: params.Scope}
+			if token.Scope == "" {
+				token.Scope = api.TokenScopeFull
+			}
+			token.Time.Created = time.Now().UTC().Format(time.RFC3339)
+			if params.Expires != nil {
+				token.Time.Expires = params.Expires.UTC().Format(time.RFC3339)
+			}
+			tokens = append(m.tokens[:len(m.tokens):len(m.tokens)], token)
+		}
+		return TokenAddedMsg{
+			newToken: response,
+			tokens:   tokens,
+		}
+	}
+}
+
 func (m model) TokensUpdate(msg tea.Msg) (model, tea.Cmd) {
 	m.state.footer.commands = []footerCommand{
 		{key: "↑/↓", value: "navigate"},
 		{key: "x/del", value: "revoke"},
 		{key: "esc", value: "back"},
 	}
+	if m.isNewTokenSelected() {
+		m.state.footer.commands = append(
+			[]footerCommand{{key: "y", value: "copy"}},
+			m.state.footer.commands...,
+		)
+	}
+
+	switch msg := msg.(type) {
+	case TokenAddedMsg:
+		m.state.tokens.form = nil
+		m.state.tokens.creating = false
+		m.state.tokens.newToken = &msg.newToken
+		m.tokens = msg.tokens
+		return m, m.Copy("token", msg.newToken.Token)
+	case VisibleError:
+		m.state.tokens.form = nil
+		m.state.tokens.creating = false
+		m.state.tokens.error = msg.message
+		return m, nil
+	}
 
-	cmds := []tea.Cmd{}
+	if m.state.tokens.form != nil {
+		return m.tokensFormUpdate(msg)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -60,7 +218,7 @@ func (m model) TokensUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.previousToken()
 			}
 		case "delete", "d", "backspace", "x":
-			if m.state.tokens.deleting == nil {
+			if m.state.tokens.deleting == nil && m.state.tokens.selected < len(m.tokens) {
 				m.state.tokens.deleting = &m.state.tokens.selected
 			}
 			return m, nil
@@ -72,56 +230,59 @@ func (m model) TokensUpdate(msg tea.Msg) (model, tea.Cmd) {
 					m.state.account.focused = false
 				}
 				return m, func() tea.Msg {
-					tokens, err := m.client.Token.List(m.context)
+					tokens, err := api.ListTokens(m.context, m.client)
 					if err != nil {
+						return VisibleError{message: api.GetErrorMessage(err)}
 					}
-					return tokens.Data
+					return tokens
 				}
 			}
+			if m.isNewTokenSelected() {
+				return m, m.Copy("token", m.state.tokens.newToken.Token)
+			}
 			return m, nil
 		case "n", "esc":
 			m.state.tokens.deleting = nil
 			return m, nil
 		case "enter":
 			if m.state.tokens.deleting == nil && m.state.tokens.selected == len(m.tokens) {
-				return m, func() tea.Msg {
-					response, err := m.client.Token.New(m.context)
-					if err != nil {
-						return VisibleError{message: api.GetErrorMessage(err)}
-					}
-					tokens, _ := m.client.Token.List(m.context)
-					// if m.output != nil {
-					// 	m.output.Copy(m.state.tokens.newToken.Token)
-					// }
-					return TokenAddedMsg{
-						newToken: response.Data,
-						tokens:   tokens.Data,
-					}
-				}
+				return m.tokensFormStart()
 			}
 		}
-	case TokenAddedMsg:
-		m.state.tokens.newToken = &msg.newToken
-		m.tokens = msg.tokens
 	}
 
-	return m, tea.Batch(cmds...)
+	return m, nil
 }
 
-func (m model) formatToken(token terminal.Token, totalWidth int) string {
-	space := totalWidth - lipgloss.Width(
-		token.ID,
-	)
+// formatDate shows an RFC 3339 timestamp from the API as a date.
+func formatDate(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format("Jan 2, 2006")
+	}
+	return value
+}
+
+func (m model) formatToken(token api.Token, totalWidth int) string {
+	name := token.Label
+	if name == "" {
+		name = token.ID
+	}
+	expired := token.Expired(time.Now())
+	status := ""
+	if expired {
+		status = "expired"
+	}
+
+	space := totalWidth - lipgloss.Width(name) - lipgloss.Width(status) - 2
 	content := lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		m.theme.TextAccent().Render(token.ID),
+		m.theme.TextAccent().Render(name),
 		m.theme.Base().Width(space).Render(),
-		// m.theme.Base().Render(price),
+		m.theme.TextError().Render(status),
 	)
 
 	lines := []string{}
 	lines = append(lines, content)
-	lines = append(lines, "created: "+token.Time.Created)
 
 	if m.state.tokens.newToken != nil && token.ID == m.state.tokens.newToken.ID {
 		lines = append(
@@ -133,6 +294,22 @@ func (m model) formatToken(token terminal.Token, totalWidth int) string {
 		lines = append(lines, token.Token)
 	}
 
+	lines = append(lines, "scope: "+scopeName(token.Scope))
+	lines = append(lines, "created: "+formatDate(token.Time.Created))
+	switch {
+	case token.Time.Expires == "":
+		lines = append(lines, "expires: never")
+	case expired:
+		lines = append(lines, m.theme.TextError().Render("expired: "+formatDate(token.Time.Expires)))
+	default:
+		lines = append(lines, "expires: "+formatDate(token.Time.Expires))
+	}
+	if token.Time.Used == "" {
+		lines = append(lines, "last used: never")
+	} else {
+		lines = append(lines, "last used: "+formatDate(token.Time.Used))
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
@@ -141,6 +318,9 @@ func (m model) TokensView(totalWidth int, focused bool) string {
 	accent := m.theme.TextAccent().Render
 
 	tokens := []string{}
+	if m.state.tokens.error != "" {
+		tokens = append(tokens, m.theme.TextError().Width(totalWidth).Render(m.state.tokens.error))
+	}
 	for i, token := range m.tokens {
 		content := m.formatToken(token, totalWidth)
 		if m.state.tokens.deleting != nil && *m.state.tokens.deleting == i {
@@ -155,12 +335,17 @@ func (m model) TokensView(totalWidth int, focused bool) string {
 	}
 
 	newTokenIndex := len(m.tokens)
-	newToken := m.CreateCenteredBoxCustom(
-		"add access token",
+	newToken := "add access token"
+	if m.state.tokens.creating {
+		newToken = "creating..."
+	} else if m.state.tokens.form != nil {
+		newToken = m.state.tokens.form.WithWidth(totalWidth - 4).View()
+	}
+	tokens = append(tokens, m.CreateCenteredBoxCustom(
+		newToken,
 		focused && m.state.tokens.selected == newTokenIndex,
 		totalWidth,
-	)
-	tokens = append(tokens, newToken)
+	))
 	tokenList := lipgloss.JoinVertical(lipgloss.Left, tokens...)
 
 	return m.theme.Base().Render(lipgloss.JoinVertical(
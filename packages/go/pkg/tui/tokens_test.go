@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestTokensCreate(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.press("a", "j", "j", "enter")
+
+	// label, read-only scope, expires in 30 days. c and a are header
+	// shortcuts, the form gets them while it's open.
+	h.press("enter")
+	h.press("c", "a", "c", "h", "e", "enter")
+	h.press("enter")
+	h.press("j", "j", "enter")
+
+	h.expect("cache")
+	h.expect("(will not be shown again)")
+	h.expect("scope: read-only orders")
+	h.expect("expires: " + time.Now().AddDate(0, 0, 30).Format("Jan 2, 2006"))
+	h.expect("last used: never")
+
+	m := h.model.(model)
+	token := m.state.tokens.newToken
+	if token == nil {
+		t.Fatal("expected a new token")
+	}
+	client := terminal.NewClient(
+		option.WithBaseURL(h.server.URL),
+		option.WithBearerToken(token.Token),
+	)
+	if _, err := client.Order.List(m.context); err != nil {
+		t.Fatal(err)
+	}
+	h.send(m.LoadTokens()())
+	h.expect("last used: " + time.Now().Format("Jan 2, 2006"))
+}
+
+func TestTokensExpired(t *testing.T) {
+	h := newHarness(t, 100, 30)
+	h.press("a", "j", "j", "enter")
+
+	expires := time.Now().AddDate(0, 0, -1).UTC()
+	h.send([]api.Token{{
+		ID:    "pat_expired",
+		Token: "trm_test_******abcd",
+		Label: "deploy",
+		Scope: api.TokenScopeCart,
+		Time: api.TokenTime{
+			Created: expires.AddDate(0, 0, -30).Format(time.RFC3339),
+			Expires: expires.Format(time.RFC3339),
+		},
+	}})
+
+	h.expect("expired: " + expires.Format("Jan 2, 2006"))
+	if view := h.model.View(); strings.Contains(view, "expires:") {
+		t.Errorf("expected the token to be flagged as expired\n%s", view)
+	}
+}
+
+func TestTokensListFails(t *testing.T) {
+	h := newHarness(t, 100, 40)
+	h.press("a", "j", "j", "enter")
+	for i, label := range []string{"cache", "deploy"} {
+		// the list is refetched fine the first time only
+		if i == 1 {
+			h.fake.Fail("GET /token")
+			h.press("j")
+		}
+		h.press("enter")
+		for _, char := range label {
+			h.press(string(char))
+		}
+		h.press("enter", "enter", "enter")
+	}
+
+	h.expect("deploy")
+	h.expect("(will not be shown again)")
+	tokens := h.model.(model).tokens
+	if len(tokens) != 2 || tokens[0].Label != "cache" || tokens[1].Label != "deploy" {
+		t.Fatalf("expected both tokens to be listed, got %+v", tokens)
+	}
+}
@@ -3,6 +3,7 @@ package validate
 import (
 	"fmt"
 	"net/mail"
+	"net/url"
 )
 
 type ErrorHandler func(str string) error
@@ -104,6 +105,16 @@ func EmailValidator(str string) error {
 	return nil
 }
 
+// URLValidator accepts absolute URLs, like OAuth redirect URIs. Custom
+// schemes are allowed for native apps.
+func URLValidator(str string) error {
+	u, err := url.Parse(str)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+		return fmt.Errorf("not a valid url")
+	}
+	return nil
+}
+
 func Compose(input ...ErrorHandler) ErrorHandler {
 	return func(str string) error {
 		for _, f := range input {